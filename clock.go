@@ -0,0 +1,128 @@
+package kiwano
+
+import "time"
+
+// fpsSmoothing is the weight of the last frame in the smoothed FPS
+const fpsSmoothing = 0.1
+
+var (
+	frameCount uint64
+	elapsed    time.Duration
+	gameTime   time.Duration
+	fps        float64
+
+	targetFPS int
+	timeScale float32 = 1
+	paused    bool
+)
+
+// FrameCount returns the number of frames stepped since the engine started
+func FrameCount() uint64 {
+	return frameCount
+}
+
+// Elapsed returns the total time of the frames stepped since the engine
+// started, ignoring the time scale and pauses
+func Elapsed() time.Duration {
+	return elapsed
+}
+
+// GameTime returns the total time seen by the scenes, which is scaled by
+// the time scale and stops while the game is paused
+func GameTime() time.Duration {
+	return gameTime
+}
+
+// FPS returns the number of frames per second, smoothed over the last frames
+func FPS() float64 {
+	return fps
+}
+
+// TargetFPS returns the frame rate cap, 0 if the frame rate is not limited
+func TargetFPS() int {
+	return targetFPS
+}
+
+// SetTargetFPS limits the frame rate of MainLoop when vsync is off, 0
+// removes the limit. Headless windows are never limited.
+func SetTargetFPS(value int) {
+	if value < 0 {
+		value = 0
+	}
+	targetFPS = value
+}
+
+// TimeScale returns the factor applied to the update deltas, 1 by default
+func TimeScale() float32 {
+	return timeScale
+}
+
+// SetTimeScale speeds up the game when scale is greater than 1 and slows
+// it down when it is lower, rendering and input are not affected
+func SetTimeScale(scale float32) {
+	if scale < 0 {
+		scale = 0
+	}
+	timeScale = scale
+}
+
+// Pause stops updating the current scene. The scene is still rendered and
+// the input is still processed, so that a pause menu keeps working. Scenes
+// becoming current while paused are paused too.
+func Pause() {
+	if paused {
+		return
+	}
+	paused = true
+	if CurrentScene != nil {
+		pauseScene(CurrentScene)
+	}
+}
+
+// Resume updates the current scene again after Pause
+func Resume() {
+	if !paused {
+		return
+	}
+	paused = false
+	if CurrentScene != nil {
+		resumeScene(CurrentScene)
+	}
+}
+
+// Paused reports whether the game is paused
+func Paused() bool {
+	return paused
+}
+
+// tick advances the clock by a frame of dt and returns the delta to update
+// the scene with, which is 0 while paused
+func tick(dt time.Duration) time.Duration {
+	frameCount++
+	elapsed += dt
+	if dt > 0 {
+		if frameFPS := float64(time.Second) / float64(dt); fps == 0 {
+			fps = frameFPS
+		} else {
+			fps += (frameFPS - fps) * fpsSmoothing
+		}
+	}
+
+	if paused {
+		return 0
+	}
+	dt = time.Duration(float64(dt) * float64(timeScale))
+	gameTime += dt
+	return dt
+}
+
+// limitFrameRate waits until the frame which started at start lasted
+// 1/TargetFPS seconds
+func limitFrameRate(start time.Time) {
+	if targetFPS == 0 || MainWindow.Vsync || MainWindow.Headless() {
+		return
+	}
+	if wait := time.Second/time.Duration(targetFPS) - time.Since(start); wait > 0 {
+		time.Sleep(wait)
+	}
+}
@@ -1,9 +1,9 @@
 package kiwano
 
-type Color struct {
-	R, G, B float32
-	Alpha   float32
-}
+import "kiwanoengine.com/kiwano/render"
+
+// Color is an alias of render.Color so that it can be shared with nodes
+type Color = render.Color
 
 func ColorRGB(r, g, b float32) Color {
 	return ColorRGBA(r, g, b, 1.0)
@@ -17,7 +17,3 @@ func ColorRGBA(r, g, b, a float32) Color {
 		Alpha: a,
 	}
 }
-
-func (c *Color) ToVec4() (float32, float32, float32, float32) {
-	return c.R, c.G, c.B, c.Alpha
-}
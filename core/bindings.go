@@ -0,0 +1,41 @@
+package core
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+// BindingStorage stores maps of strings, like the bindings of
+// input.SaveBindings, in the local storage. Unlike Get and Save, errors
+// are returned, including leveldb.ErrNotFound for a missing key.
+type BindingStorage struct{}
+
+// Get returns the map stored under key by Save
+func (BindingStorage) Get(key string) (map[string]string, error) {
+	if db == nil {
+		return nil, errors.New("local storage is not open")
+	}
+	data, err := db.Get([]byte(key), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]string
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Save stores a map under key
+func (BindingStorage) Save(key string, m map[string]string) error {
+	if db == nil {
+		return errors.New("local storage is not open")
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(m); err != nil {
+		return err
+	}
+	return db.Put([]byte(key), buf.Bytes(), nil)
+}
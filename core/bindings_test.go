@@ -0,0 +1,54 @@
+package core
+
+import (
+	"os"
+	"testing"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+func TestMain(m *testing.M) {
+	// Use a temporary store instead of the one opened in the package
+	// directory by init
+	if db != nil {
+		db.Close()
+	}
+	os.RemoveAll("tmp")
+	dir, err := os.MkdirTemp("", "core")
+	if err != nil {
+		panic(err)
+	}
+	db, err = leveldb.OpenFile(dir, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	db.Close()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestBindingStorage(t *testing.T) {
+	var storage BindingStorage
+	if _, err := storage.Get("bindings"); err != leveldb.ErrNotFound {
+		t.Errorf("missing key returned %v, want %v", err, leveldb.ErrNotFound)
+	}
+
+	want := map[string]string{"jump": "key:32:1:0,button:0:1:-1", "move_x": ""}
+	if err := storage.Save("bindings", want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := storage.Get("bindings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%v is %q, want %q", k, got[k], v)
+		}
+	}
+}
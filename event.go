@@ -0,0 +1,80 @@
+package kiwano
+
+import (
+	"time"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// InputHandler receives the input events of the main window. The input
+// package registers itself as a handler so that the engine core doesn't
+// depend on it.
+type InputHandler interface {
+	// NewFrame is called before the events of a new frame are polled
+	NewFrame()
+	OnKey(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey)
+	OnChar(char rune)
+	OnMouseButton(button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey)
+	// OnCursorPos receives the cursor position in window coordinates
+	OnCursorPos(x, y float64)
+	OnCursorEnter(entered bool)
+	OnScroll(xoff, yoff float64)
+}
+
+var inputHandlers []InputHandler
+
+// AddInputHandler registers a handler for the input events of the main window
+func AddInputHandler(h InputHandler) {
+	inputHandlers = append(inputHandlers, h)
+}
+
+var deltaHook func(time.Duration) time.Duration
+
+// SetDeltaHook installs a function called by RunFrame before each frame
+// with the measured wall-clock delta, the returned delta is used instead.
+// The input package uses it to record and replay frame times.
+func SetDeltaHook(hook func(dt time.Duration) time.Duration) {
+	deltaHook = hook
+}
+
+func newInputFrame() {
+	for _, h := range inputHandlers {
+		h.NewFrame()
+	}
+}
+
+func (w *Window) onKeyCallback(win *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	for _, h := range inputHandlers {
+		h.OnKey(key, scancode, action, mods)
+	}
+}
+
+func (w *Window) onCharCallback(win *glfw.Window, char rune) {
+	for _, h := range inputHandlers {
+		h.OnChar(char)
+	}
+}
+
+func (w *Window) onMouseButtonCallback(win *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+	for _, h := range inputHandlers {
+		h.OnMouseButton(button, action, mods)
+	}
+}
+
+func (w *Window) onCursorPosCallback(win *glfw.Window, x, y float64) {
+	for _, h := range inputHandlers {
+		h.OnCursorPos(x, y)
+	}
+}
+
+func (w *Window) onCursorEnterCallback(win *glfw.Window, entered bool) {
+	for _, h := range inputHandlers {
+		h.OnCursorEnter(entered)
+	}
+}
+
+func (w *Window) onScrollCallback(win *glfw.Window, xoff, yoff float64) {
+	for _, h := range inputHandlers {
+		h.OnScroll(xoff, yoff)
+	}
+}
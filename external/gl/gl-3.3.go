@@ -59,6 +59,21 @@ func Viewport(x int32, y int32, width int32, height int32) {
 	gl.Viewport(x, y, width, height)
 }
 
+// Enable enable server-side GL capabilities
+func Enable(cap uint32) {
+	gl.Enable(cap)
+}
+
+// Disable disable server-side GL capabilities
+func Disable(cap uint32) {
+	gl.Disable(cap)
+}
+
+// BlendFunc specify pixel arithmetic
+func BlendFunc(sfactor uint32, dfactor uint32) {
+	gl.BlendFunc(sfactor, dfactor)
+}
+
 // CreateProgram creates a program object
 func CreateProgram() uint32 {
 	return gl.CreateProgram()
@@ -153,6 +168,11 @@ func BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
 	gl.BufferData(target, size, data, usage)
 }
 
+// BufferSubData updates a subset of a buffer object's data store
+func BufferSubData(target uint32, offset int, size int, data unsafe.Pointer) {
+	gl.BufferSubData(target, offset, size, data)
+}
+
 // BindVertexBuffer bind a buffer to a vertex buffer bind point
 func BindVertexBuffer(bindingindex uint32, buffer uint32, offset int, stride int32) {
 	gl.BindVertexBuffer(bindingindex, buffer, offset, stride)
@@ -193,6 +213,11 @@ func GenTextures(n int32, textures *uint32) {
 	gl.GenTextures(n, textures)
 }
 
+// DeleteTextures delete named textures
+func DeleteTextures(n int32, textures *uint32) {
+	gl.DeleteTextures(n, textures)
+}
+
 // ActiveTexture select active texture unit
 func ActiveTexture(texture uint32) {
 	gl.ActiveTexture(texture)
@@ -220,6 +245,37 @@ func GenerateMipmap(target uint32) {
 	gl.GenerateMipmap(target)
 }
 
+// GenFramebuffers generate framebuffer object names
+func GenFramebuffers(n int32, framebuffers *uint32) {
+	gl.GenFramebuffers(n, framebuffers)
+}
+
+// BindFramebuffer bind a framebuffer to a framebuffer target
+func BindFramebuffer(target uint32, framebuffer uint32) {
+	gl.BindFramebuffer(target, framebuffer)
+}
+
+// FramebufferTexture2D attach a level of a texture object as a logical
+// buffer of a framebuffer object
+func FramebufferTexture2D(target uint32, attachment uint32, textarget uint32, texture uint32, level int32) {
+	gl.FramebufferTexture2D(target, attachment, textarget, texture, level)
+}
+
+// CheckFramebufferStatus check the completeness status of a framebuffer
+func CheckFramebufferStatus(target uint32) uint32 {
+	return gl.CheckFramebufferStatus(target)
+}
+
+// DeleteFramebuffers delete framebuffer objects
+func DeleteFramebuffers(n int32, framebuffers *uint32) {
+	gl.DeleteFramebuffers(n, framebuffers)
+}
+
+// ReadPixels read a block of pixels from the frame buffer
+func ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	gl.ReadPixels(x, y, width, height, format, xtype, pixels)
+}
+
 // GetUniformLocation returns the location of a uniform variable
 func GetUniformLocation(program uint32, name string) int32 {
 	return gl.GetUniformLocation(program, gl.Str(name))
@@ -265,6 +321,11 @@ func Uniform4f(location int32, v0, v1, v2, v3 float32) {
 	gl.Uniform4f(location, v0, v1, v2, v3)
 }
 
+// UniformMatrix3fv ...
+func UniformMatrix3fv(location int32, count int32, transpose bool, value *float32) {
+	gl.UniformMatrix3fv(location, count, transpose, value)
+}
+
 // DrawElements render primitives from array data
 func DrawElements(mode uint32, count int32, xtype uint32, indices unsafe.Pointer) {
 	gl.DrawElements(mode, count, xtype, indices)
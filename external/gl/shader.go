@@ -4,6 +4,16 @@ import (
 	"fmt"
 )
 
+// CompileError is returned when a shader fails to compile, Log is the info
+// log of the driver
+type CompileError struct {
+	Log string
+}
+
+func (e *CompileError) Error() string {
+	return fmt.Sprintf("Failed to compile shader: %v", e.Log)
+}
+
 func CreateAndCompileShader(shaderType uint32, src string) (uint32, error) {
 	shader := CreateShader(shaderType)
 	ShaderSource(shader, src)
@@ -11,7 +21,8 @@ func CreateAndCompileShader(shaderType uint32, src string) (uint32, error) {
 
 	if ret := GetShader(shader, COMPILE_STATUS); ret != TRUE {
 		log := GetShaderInfoLog(shader)
-		return 0, fmt.Errorf("Failed to compile %v: %v", src, log)
+		DeleteShader(shader)
+		return 0, &CompileError{log}
 	}
 	return shader, nil
 }
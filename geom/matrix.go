@@ -0,0 +1,125 @@
+package geom
+
+import "math"
+
+// Matrix3 is a 3x3 matrix representing a 2D affine transform. Elements are
+// stored in column-major order so that the matrix can be uploaded to OpenGL
+// directly:
+//
+//	| m[0] m[3] m[6] |
+//	| m[1] m[4] m[7] |
+//	| m[2] m[5] m[8] |
+type Matrix3 [9]float32
+
+// Identity returns the identity matrix
+func Identity() Matrix3 {
+	return Matrix3{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// Translation returns a matrix translating by (x, y)
+func Translation(x, y float32) Matrix3 {
+	return Matrix3{
+		1, 0, 0,
+		0, 1, 0,
+		x, y, 1,
+	}
+}
+
+// Scaling returns a matrix scaling by (x, y)
+func Scaling(x, y float32) Matrix3 {
+	return Matrix3{
+		x, 0, 0,
+		0, y, 0,
+		0, 0, 1,
+	}
+}
+
+// Rotation returns a matrix rotating by angle degrees. With the y axis
+// pointing down, positive angles rotate clockwise.
+func Rotation(angle float32) Matrix3 {
+	s, c := math.Sincos(float64(angle) * math.Pi / 180)
+	sin, cos := float32(s), float32(c)
+	return Matrix3{
+		cos, sin, 0,
+		-sin, cos, 0,
+		0, 0, 1,
+	}
+}
+
+// Skewing returns a matrix skewing by x degrees along the x axis and by y
+// degrees along the y axis
+func Skewing(x, y float32) Matrix3 {
+	tx := float32(math.Tan(float64(x) * math.Pi / 180))
+	ty := float32(math.Tan(float64(y) * math.Pi / 180))
+	return Matrix3{
+		1, ty, 0,
+		tx, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// Ortho returns a projection matrix mapping the area between left, right,
+// bottom and top to OpenGL normalized device coordinates, like glOrtho
+func Ortho(left, right, bottom, top float32) Matrix3 {
+	return Matrix3{
+		2 / (right - left), 0, 0,
+		0, 2 / (top - bottom), 0,
+		-(right + left) / (right - left), -(top + bottom) / (top - bottom), 1,
+	}
+}
+
+// Mul returns m * o, the transform applying o first and then m
+func (m Matrix3) Mul(o Matrix3) Matrix3 {
+	var r Matrix3
+	for col := 0; col < 3; col++ {
+		for row := 0; row < 3; row++ {
+			r[col*3+row] = m[row]*o[col*3] + m[3+row]*o[col*3+1] + m[6+row]*o[col*3+2]
+		}
+	}
+	return r
+}
+
+// Transform applies the matrix to the point (x, y)
+func (m Matrix3) Transform(x, y float32) (float32, float32) {
+	return m[0]*x + m[3]*y + m[6], m[1]*x + m[4]*y + m[7]
+}
+
+// TransformVec2 applies the matrix to a point
+func (m Matrix3) TransformVec2(v Vec2) Vec2 {
+	x, y := m.Transform(v.X, v.Y)
+	return Vec2{x, y}
+}
+
+// Determinant returns the determinant of the matrix
+func (m Matrix3) Determinant() float32 {
+	return m[0]*(m[4]*m[8]-m[7]*m[5]) -
+		m[3]*(m[1]*m[8]-m[7]*m[2]) +
+		m[6]*(m[1]*m[5]-m[4]*m[2])
+}
+
+// Invert returns the inverse of the matrix, ok is false if the matrix is
+// not invertible
+func (m Matrix3) Invert() (inv Matrix3, ok bool) {
+	det := m.Determinant()
+	if det == 0 {
+		return Matrix3{}, false
+	}
+
+	d := 1 / det
+	inv = Matrix3{
+		(m[4]*m[8] - m[7]*m[5]) * d,
+		(m[7]*m[2] - m[1]*m[8]) * d,
+		(m[1]*m[5] - m[4]*m[2]) * d,
+		(m[6]*m[5] - m[3]*m[8]) * d,
+		(m[0]*m[8] - m[6]*m[2]) * d,
+		(m[3]*m[2] - m[0]*m[5]) * d,
+		(m[3]*m[7] - m[6]*m[4]) * d,
+		(m[6]*m[1] - m[0]*m[7]) * d,
+		(m[0]*m[4] - m[3]*m[1]) * d,
+	}
+	return inv, true
+}
@@ -0,0 +1,125 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+const epsilon = 1e-5
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < epsilon
+}
+
+func nearMatrix(a, b Matrix3) bool {
+	for i := range a {
+		if !near(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Matrix3
+		in   Vec2
+		want Vec2
+	}{
+		{"identity", Identity(), Vec2{3, 4}, Vec2{3, 4}},
+		{"translation", Translation(10, -5), Vec2{3, 4}, Vec2{13, -1}},
+		{"scaling", Scaling(2, -3), Vec2{3, 4}, Vec2{6, -12}},
+		// With y pointing down, positive angles turn clockwise on screen
+		{"rotation 90", Rotation(90), Vec2{1, 0}, Vec2{0, 1}},
+		{"rotation 180", Rotation(180), Vec2{1, 2}, Vec2{-1, -2}},
+		{"rotation -90", Rotation(-90), Vec2{0, 1}, Vec2{1, 0}},
+		{"skewing x", Skewing(45, 0), Vec2{0, 1}, Vec2{1, 1}},
+		{"skewing y", Skewing(0, 45), Vec2{1, 0}, Vec2{1, 1}},
+		{"skewing x keeps x axis", Skewing(45, 0), Vec2{2, 0}, Vec2{2, 0}},
+		{"ortho left top", Ortho(0, 800, 600, 0), Vec2{0, 0}, Vec2{-1, 1}},
+		{"ortho right bottom", Ortho(0, 800, 600, 0), Vec2{800, 600}, Vec2{1, -1}},
+		{"ortho center", Ortho(0, 800, 600, 0), Vec2{400, 300}, Vec2{0, 0}},
+	}
+	for _, tt := range tests {
+		got := tt.m.TransformVec2(tt.in)
+		if !near(got.X, tt.want.X) || !near(got.Y, tt.want.Y) {
+			t.Errorf("%v: %v transformed to %v, want %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Matrix3
+		in   Vec2
+		want Vec2
+	}{
+		// Mul applies the right matrix first
+		{"translate then scale", Scaling(2, 2).Mul(Translation(1, 0)), Vec2{0, 0}, Vec2{2, 0}},
+		{"scale then translate", Translation(1, 0).Mul(Scaling(2, 2)), Vec2{0, 0}, Vec2{1, 0}},
+		{"rotate then translate", Translation(10, 0).Mul(Rotation(90)), Vec2{1, 0}, Vec2{10, 1}},
+		{"identity left", Identity().Mul(Translation(3, 4)), Vec2{1, 1}, Vec2{4, 5}},
+		{"identity right", Translation(3, 4).Mul(Identity()), Vec2{1, 1}, Vec2{4, 5}},
+	}
+	for _, tt := range tests {
+		got := tt.m.TransformVec2(tt.in)
+		if !near(got.X, tt.want.X) || !near(got.Y, tt.want.Y) {
+			t.Errorf("%v: %v transformed to %v, want %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestInvert(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Matrix3
+		ok   bool
+	}{
+		{"identity", Identity(), true},
+		{"translation", Translation(10, -5), true},
+		{"scaling", Scaling(2, 0.5), true},
+		{"rotation", Rotation(30), true},
+		{"skewing", Skewing(20, -10), true},
+		{"composed", Translation(5, 6).Mul(Rotation(45)).Mul(Skewing(10, 0)).Mul(Scaling(3, 2)), true},
+		{"ortho", Ortho(0, 800, 600, 0), true},
+		{"zero scale", Scaling(0, 1), false},
+		{"zero", Matrix3{}, false},
+	}
+	for _, tt := range tests {
+		inv, ok := tt.m.Invert()
+		if ok != tt.ok {
+			t.Errorf("%v: invertible is %v, want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if got := tt.m.Mul(inv); !nearMatrix(got, Identity()) {
+			t.Errorf("%v: m * inverse is %v, want identity", tt.name, got)
+		}
+		if got := inv.Mul(tt.m); !nearMatrix(got, Identity()) {
+			t.Errorf("%v: inverse * m is %v, want identity", tt.name, got)
+		}
+	}
+}
+
+func TestDeterminant(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Matrix3
+		want float32
+	}{
+		{"identity", Identity(), 1},
+		{"translation", Translation(7, 8), 1},
+		{"scaling", Scaling(2, 3), 6},
+		{"rotation", Rotation(37), 1},
+		{"mirror", Scaling(-1, 1), -1},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Determinant(); !near(got, tt.want) {
+			t.Errorf("%v: determinant is %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
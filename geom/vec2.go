@@ -0,0 +1,44 @@
+package geom
+
+import "math"
+
+// Vec2 is a 2D vector
+type Vec2 struct {
+	X, Y float32
+}
+
+// Add returns v + o
+func (v Vec2) Add(o Vec2) Vec2 {
+	return Vec2{v.X + o.X, v.Y + o.Y}
+}
+
+// Sub returns v - o
+func (v Vec2) Sub(o Vec2) Vec2 {
+	return Vec2{v.X - o.X, v.Y - o.Y}
+}
+
+// Mul returns v scaled by s
+func (v Vec2) Mul(s float32) Vec2 {
+	return Vec2{v.X * s, v.Y * s}
+}
+
+// Length returns the length of v
+func (v Vec2) Length() float32 {
+	return float32(math.Hypot(float64(v.X), float64(v.Y)))
+}
+
+// Size is a 2D size
+type Size struct {
+	Width, Height float32
+}
+
+// Rect is an axis-aligned rectangle
+type Rect struct {
+	X, Y          float32
+	Width, Height float32
+}
+
+// Contains reports whether the point lies inside the rectangle
+func (r Rect) Contains(p Vec2) bool {
+	return p.X >= r.X && p.X < r.X+r.Width && p.Y >= r.Y && p.Y < r.Y+r.Height
+}
@@ -0,0 +1,357 @@
+package input
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BindingKind is the type of input a binding reads
+type BindingKind int
+
+const (
+	BindKey BindingKind = iota
+	BindMouseButton
+	BindGamepadButton
+	BindGamepadAxis
+)
+
+var bindingKindNames = []string{"key", "mouse", "button", "axis"}
+
+// AnyGamepad makes a gamepad binding read all the connected gamepads
+const AnyGamepad Gamepad = -1
+
+// PressThreshold is the value above which an action reads as pressed
+const PressThreshold = 0.5
+
+// Binding maps a key, mouse button or gamepad element to an action
+type Binding struct {
+	Kind BindingKind
+	// Code is the Key, MouseButton, GamepadButton or GamepadAxis
+	Code int
+	// Scale multiplies the value of the binding, -1 lets a key or a button
+	// push an axis in the negative direction
+	Scale float32
+	// Gamepad is the gamepad read by gamepad bindings, or AnyGamepad
+	Gamepad Gamepad
+}
+
+// KeyBinding binds a key
+func KeyBinding(key Key) Binding {
+	return Binding{BindKey, int(key), 1, AnyGamepad}
+}
+
+// MouseBinding binds a mouse button
+func MouseBinding(button MouseButton) Binding {
+	return Binding{BindMouseButton, int(button), 1, AnyGamepad}
+}
+
+// GamepadButtonBinding binds a button of any gamepad
+func GamepadButtonBinding(button GamepadButton) Binding {
+	return Binding{BindGamepadButton, int(button), 1, AnyGamepad}
+}
+
+// GamepadAxisBinding binds an axis of any gamepad
+func GamepadAxisBinding(axis GamepadAxis) Binding {
+	return Binding{BindGamepadAxis, int(axis), 1, AnyGamepad}
+}
+
+// Negative returns the binding pushing its action in the negative direction
+func (b Binding) Negative() Binding {
+	b.Scale = -b.Scale
+	return b
+}
+
+// ForGamepad returns the binding restricted to one gamepad
+func (b Binding) ForGamepad(g Gamepad) Binding {
+	b.Gamepad = g
+	return b
+}
+
+func (b Binding) gamepads() []Gamepad {
+	if b.Gamepad == AnyGamepad {
+		return Gamepads()
+	}
+	return []Gamepad{b.Gamepad}
+}
+
+// value returns the current value of the binding in [-1, 1]
+func (b Binding) value() float32 {
+	var v float32
+	switch b.Kind {
+	case BindKey:
+		if Pressed(Key(b.Code)) {
+			v = 1
+		}
+	case BindMouseButton:
+		if MousePressed(MouseButton(b.Code)) {
+			v = 1
+		}
+	case BindGamepadButton:
+		for _, g := range b.gamepads() {
+			if g.Pressed(GamepadButton(b.Code)) {
+				v = 1
+			}
+		}
+	case BindGamepadAxis:
+		for _, g := range b.gamepads() {
+			if a := g.Axis(GamepadAxis(b.Code)); abs(a) > abs(v) {
+				v = a
+			}
+		}
+	}
+	return v * b.Scale
+}
+
+// edges reports whether a digital binding was pressed or released since
+// the last frame, so that short presses are not missed
+func (b Binding) edges() (pressed, released bool) {
+	switch b.Kind {
+	case BindKey:
+		return JustPressed(Key(b.Code)), JustReleased(Key(b.Code))
+	case BindMouseButton:
+		return MouseJustPressed(MouseButton(b.Code)), MouseJustReleased(MouseButton(b.Code))
+	case BindGamepadButton:
+		for _, g := range b.gamepads() {
+			pressed = pressed || g.JustPressed(GamepadButton(b.Code))
+			released = released || g.JustReleased(GamepadButton(b.Code))
+		}
+	}
+	return pressed, released
+}
+
+// String encodes the binding as "kind:code:scale:gamepad"
+func (b Binding) String() string {
+	return fmt.Sprintf("%s:%d:%g:%d", bindingKindNames[b.Kind], b.Code, b.Scale, b.Gamepad)
+}
+
+// ParseBinding decodes a binding encoded by Binding.String
+func ParseBinding(s string) (Binding, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) != 4 {
+		return Binding{}, fmt.Errorf("Invalid binding %q", s)
+	}
+
+	var b Binding
+	kind := -1
+	for i, name := range bindingKindNames {
+		if name == fields[0] {
+			kind = i
+		}
+	}
+	code, err1 := strconv.Atoi(fields[1])
+	scale, err2 := strconv.ParseFloat(fields[2], 32)
+	gamepad, err3 := strconv.Atoi(fields[3])
+	if kind < 0 || err1 != nil || err2 != nil || err3 != nil {
+		return b, fmt.Errorf("Invalid binding %q", s)
+	}
+
+	b.Kind = BindingKind(kind)
+	b.Code = code
+	b.Scale = float32(scale)
+	b.Gamepad = Gamepad(gamepad)
+	return b, nil
+}
+
+// ActionMapping is a logical action, like "jump" or "move_x", bound to
+// one or more inputs
+type ActionMapping struct {
+	name     string
+	bindings []Binding
+	wasDown  bool
+}
+
+var actions = make(map[string]*ActionMapping)
+
+// Action returns the action with the given name, creating it if needed
+func Action(name string) *ActionMapping {
+	a, ok := actions[name]
+	if !ok {
+		a = &ActionMapping{name: name}
+		actions[name] = a
+	}
+	return a
+}
+
+// Name returns the name of the action
+func (a *ActionMapping) Name() string {
+	return a.name
+}
+
+// Bind adds bindings to the action
+func (a *ActionMapping) Bind(bindings ...Binding) *ActionMapping {
+	for _, b := range bindings {
+		if !a.hasBinding(b) {
+			a.bindings = append(a.bindings, b)
+		}
+	}
+	return a
+}
+
+// Unbind removes a binding from the action
+func (a *ActionMapping) Unbind(b Binding) {
+	for i, binding := range a.bindings {
+		if binding == b {
+			a.bindings = append(a.bindings[:i], a.bindings[i+1:]...)
+			return
+		}
+	}
+}
+
+// ClearBindings removes all the bindings of the action
+func (a *ActionMapping) ClearBindings() {
+	a.bindings = nil
+}
+
+// Bindings returns the bindings of the action
+func (a *ActionMapping) Bindings() []Binding {
+	return append([]Binding(nil), a.bindings...)
+}
+
+func (a *ActionMapping) hasBinding(b Binding) bool {
+	for _, binding := range a.bindings {
+		if binding == b {
+			return true
+		}
+	}
+	return false
+}
+
+// Value returns the sum of the binding values clamped to [-1, 1], for
+// actions used as an axis
+func (a *ActionMapping) Value() float32 {
+	var v float32
+	for _, b := range a.bindings {
+		v += b.value()
+	}
+	if v > 1 {
+		return 1
+	}
+	if v < -1 {
+		return -1
+	}
+	return v
+}
+
+// Pressed reports whether any binding is held
+func (a *ActionMapping) Pressed() bool {
+	for _, b := range a.bindings {
+		if abs(b.value()) >= PressThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// JustPressed reports whether the action was pressed since the last frame
+func (a *ActionMapping) JustPressed() bool {
+	if a.Pressed() && !a.wasDown {
+		return true
+	}
+	for _, b := range a.bindings {
+		if pressed, _ := b.edges(); pressed {
+			return true
+		}
+	}
+	return false
+}
+
+// JustReleased reports whether the action was released since the last frame
+func (a *ActionMapping) JustReleased() bool {
+	if !a.Pressed() && a.wasDown {
+		return true
+	}
+	for _, b := range a.bindings {
+		if _, released := b.edges(); released {
+			return true
+		}
+	}
+	return false
+}
+
+// newActionsFrame records the state of the actions at the end of a frame
+func newActionsFrame() {
+	for _, a := range actions {
+		a.wasDown = a.Pressed()
+	}
+}
+
+// BindingStorage stores the encoded bindings of actions by action name,
+// like core.BindingStorage in the local storage or a settings file. Get
+// returns an error when nothing is stored under key.
+type BindingStorage interface {
+	Get(key string) (map[string]string, error)
+	Save(key string, bindings map[string]string) error
+}
+
+// SaveBindings stores the bindings of all the actions under key
+func SaveBindings(storage BindingStorage, key string) error {
+	data := make(map[string]string, len(actions))
+	for name, a := range actions {
+		encoded := make([]string, len(a.bindings))
+		for i, b := range a.bindings {
+			encoded[i] = b.String()
+		}
+		data[name] = strings.Join(encoded, ",")
+	}
+	return storage.Save(key, data)
+}
+
+// LoadBindings replaces the bindings of the actions stored under key by
+// SaveBindings. Actions which are not stored keep their bindings.
+func LoadBindings(storage BindingStorage, key string) error {
+	data, err := storage.Get(key)
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string][]Binding)
+	names := make([]string, 0, len(data))
+	for name, encoded := range data {
+		var bindings []Binding
+		for _, s := range strings.Split(encoded, ",") {
+			if s == "" {
+				continue
+			}
+			b, err := ParseBinding(s)
+			if err != nil {
+				return err
+			}
+			bindings = append(bindings, b)
+		}
+		loaded[name] = bindings
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		a := Action(name)
+		a.ClearBindings()
+		a.Bind(loaded[name]...)
+	}
+	return nil
+}
+
+var captureBinding func(Binding)
+
+// CaptureBinding calls fn with the next key, mouse button or gamepad input
+// pressed by the player, to rebind actions from a settings menu
+func CaptureBinding(fn func(Binding)) {
+	captureBinding = fn
+}
+
+// captured hands a pressed input to the pending CaptureBinding call
+func captured(b Binding) {
+	if fn := captureBinding; fn != nil {
+		captureBinding = nil
+		fn(b)
+	}
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,253 @@
+package input
+
+import (
+	"math"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+)
+
+// Gamepad identifies a connected controller, from 0 to MaxGamepads-1
+type Gamepad int
+
+// MaxGamepads is the number of controllers tracked at the same time
+const MaxGamepads = int(glfw.JoystickLast) + 1
+
+// GamepadButton is a button of the standard Xbox-style gamepad layout
+type GamepadButton int
+
+const (
+	GamepadA GamepadButton = iota
+	GamepadB
+	GamepadX
+	GamepadY
+	GamepadLeftBumper
+	GamepadRightBumper
+	GamepadBack
+	GamepadStart
+	GamepadGuide
+	GamepadLeftThumb
+	GamepadRightThumb
+	GamepadDPadUp
+	GamepadDPadRight
+	GamepadDPadDown
+	GamepadDPadLeft
+
+	gamepadButtonCount = iota
+)
+
+// GamepadAxis is an axis of the standard gamepad layout. Sticks range from
+// -1 to 1 with Y pointing down, triggers from 0 to 1.
+type GamepadAxis int
+
+const (
+	GamepadLeftX GamepadAxis = iota
+	GamepadLeftY
+	GamepadRightX
+	GamepadRightY
+	GamepadLeftTrigger
+	GamepadRightTrigger
+
+	gamepadAxisCount = iota
+)
+
+// DefaultDeadzone is the stick deadzone of new gamepads
+const DefaultDeadzone = 0.15
+
+// GamepadEvent is sent when a gamepad is connected or disconnected
+type GamepadEvent struct {
+	Gamepad   Gamepad
+	Connected bool
+	Name      string
+}
+
+type gamepadState struct {
+	connected bool
+	name      string
+	mapping   *gamepadMapping
+	deadzone  float32
+
+	buttons     [gamepadButtonCount]bool
+	prevButtons [gamepadButtonCount]bool
+	axes        [gamepadAxisCount]float32
+}
+
+var (
+	gamepads         [MaxGamepads]gamepadState
+	gamepadListeners []*Listener
+)
+
+func init() {
+	for i := range gamepads {
+		gamepads[i].deadzone = DefaultDeadzone
+	}
+}
+
+// Gamepads returns the connected gamepads
+func Gamepads() []Gamepad {
+	var connected []Gamepad
+	for i := range gamepads {
+		if gamepads[i].connected {
+			connected = append(connected, Gamepad(i))
+		}
+	}
+	return connected
+}
+
+// OnGamepad subscribes a function to gamepad connection events
+func OnGamepad(fn func(GamepadEvent)) *Listener {
+	l := &Listener{fn: fn}
+	gamepadListeners = append(gamepadListeners, l)
+	return l
+}
+
+func (g Gamepad) state() *gamepadState {
+	if g < 0 || int(g) >= MaxGamepads {
+		return &gamepadState{}
+	}
+	return &gamepads[g]
+}
+
+// Connected reports whether the gamepad is connected
+func (g Gamepad) Connected() bool {
+	return g.state().connected
+}
+
+// Name returns the name reported by the controller
+func (g Gamepad) Name() string {
+	return g.state().name
+}
+
+// Pressed reports whether a button is held down
+func (g Gamepad) Pressed(b GamepadButton) bool {
+	return validGamepadButton(b) && g.state().buttons[b]
+}
+
+// JustPressed reports whether a button was pressed since the last frame
+func (g Gamepad) JustPressed(b GamepadButton) bool {
+	s := g.state()
+	return validGamepadButton(b) && s.buttons[b] && !s.prevButtons[b]
+}
+
+// JustReleased reports whether a button was released since the last frame
+func (g Gamepad) JustReleased(b GamepadButton) bool {
+	s := g.state()
+	return validGamepadButton(b) && !s.buttons[b] && s.prevButtons[b]
+}
+
+// Axis returns the value of an axis after applying the deadzone
+func (g Gamepad) Axis(a GamepadAxis) float32 {
+	if a < 0 || a >= gamepadAxisCount {
+		return 0
+	}
+	return g.state().axes[a]
+}
+
+// SetDeadzone changes the radius under which stick positions read as zero
+func (g Gamepad) SetDeadzone(deadzone float32) {
+	g.state().deadzone = deadzone
+}
+
+// SetMapping overrides the mapping of the gamepad with a mapping string in
+// the SDL GameControllerDB format
+func (g Gamepad) SetMapping(mapping string) error {
+	m, _, err := parseMapping(mapping)
+	if err != nil {
+		return err
+	}
+	g.state().mapping = m
+	return nil
+}
+
+func validGamepadButton(b GamepadButton) bool {
+	return b >= 0 && b < gamepadButtonCount
+}
+
+// readJoystick reads the raw state of a joystick from GLFW
+func readJoystick(g Gamepad) JoystickState {
+	joy := glfw.Joystick(g)
+	if !glfw.JoystickPresent(joy) {
+		return JoystickState{}
+	}
+
+	return JoystickState{
+		Present: true,
+		Name:    glfw.GetJoystickName(joy),
+		// Copy the slices, GLFW reuses them on the next poll
+		Axes:    append([]float32(nil), glfw.GetJoystickAxes(joy)...),
+		Buttons: append([]byte(nil), glfw.GetJoystickButtons(joy)...),
+	}
+}
+
+// updateGamepad applies the raw state of a joystick to a gamepad
+func updateGamepad(g Gamepad, present bool, name string, axes []float32, buttons []byte) {
+	s := &gamepads[g]
+	s.prevButtons = s.buttons
+
+	if present != s.connected {
+		s.connected = present
+		s.name = name
+		if present {
+			s.mapping = gamepadMappings[name]
+			if s.mapping == nil {
+				s.mapping = xinputMapping
+			}
+		}
+		e := GamepadEvent{g, present, name}
+		dispatch(&gamepadListeners, func(fn interface{}) {
+			fn.(func(GamepadEvent))(e)
+		})
+	}
+
+	if !present {
+		s.buttons = [gamepadButtonCount]bool{}
+		s.axes = [gamepadAxisCount]float32{}
+		return
+	}
+
+	prevAxes := s.axes
+	s.buttons, s.axes = s.mapping.apply(axes, buttons)
+	applyDeadzone(&s.axes[GamepadLeftX], &s.axes[GamepadLeftY], s.deadzone)
+	applyDeadzone(&s.axes[GamepadRightX], &s.axes[GamepadRightY], s.deadzone)
+
+	if captureBinding != nil {
+		captureGamepad(g, s, prevAxes)
+	}
+}
+
+// captureGamepad hands a newly pressed button or pushed axis to the pending
+// CaptureBinding call
+func captureGamepad(g Gamepad, s *gamepadState, prevAxes [gamepadAxisCount]float32) {
+	for b := range s.buttons {
+		if s.buttons[b] && !s.prevButtons[b] {
+			captured(GamepadButtonBinding(GamepadButton(b)).ForGamepad(g))
+			return
+		}
+	}
+	for a, v := range s.axes {
+		if abs(v) >= PressThreshold && abs(prevAxes[a]) < PressThreshold {
+			binding := GamepadAxisBinding(GamepadAxis(a)).ForGamepad(g)
+			if v < 0 {
+				binding = binding.Negative()
+			}
+			captured(binding)
+			return
+		}
+	}
+}
+
+// applyDeadzone zeroes a stick inside a radial deadzone and rescales the
+// rest of the range so that values start from 0 at its edge
+func applyDeadzone(x, y *float32, deadzone float32) {
+	length := float32(math.Hypot(float64(*x), float64(*y)))
+	if length <= deadzone || deadzone >= 1 {
+		*x, *y = 0, 0
+		return
+	}
+
+	scale := (length - deadzone) / (1 - deadzone) / length
+	if length > 1 {
+		scale = 1 / length
+	}
+	*x *= scale
+	*y *= scale
+}
@@ -0,0 +1,283 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// mappingSource is an element of a raw joystick
+type mappingSource struct {
+	kind   byte // 'b' button, 'a' axis, 'h' hat
+	index  int
+	hatBit int
+	// half selects the positive (+1) or negative (-1) half of an axis
+	half   int
+	invert bool
+}
+
+// mappingTarget is an element of the standard gamepad layout
+type mappingTarget struct {
+	button GamepadButton
+	axis   GamepadAxis
+	isAxis bool
+	// half outputs to the positive (+1) or negative (-1) half of an axis
+	half int
+}
+
+type gamepadMapping struct {
+	name     string
+	elements map[mappingTarget]mappingSource
+}
+
+var (
+	mappingButtons = map[string]GamepadButton{
+		"a": GamepadA, "b": GamepadB, "x": GamepadX, "y": GamepadY,
+		"leftshoulder": GamepadLeftBumper, "rightshoulder": GamepadRightBumper,
+		"back": GamepadBack, "start": GamepadStart, "guide": GamepadGuide,
+		"leftstick": GamepadLeftThumb, "rightstick": GamepadRightThumb,
+		"dpup": GamepadDPadUp, "dpright": GamepadDPadRight,
+		"dpdown": GamepadDPadDown, "dpleft": GamepadDPadLeft,
+	}
+	mappingAxes = map[string]GamepadAxis{
+		"leftx": GamepadLeftX, "lefty": GamepadLeftY,
+		"rightx": GamepadRightX, "righty": GamepadRightY,
+		"lefttrigger": GamepadLeftTrigger, "righttrigger": GamepadRightTrigger,
+	}
+
+	// gamepadMappings are the mappings added by AddGamepadMappings, keyed
+	// by controller name
+	gamepadMappings = make(map[string]*gamepadMapping)
+)
+
+// xinputMapping matches the layout GLFW reports for XInput controllers,
+// it is used for joysticks without a known mapping
+var xinputMapping = mustParseMapping("xinput,XInput Controller," +
+	"a:b0,b:b1,x:b2,y:b3,leftshoulder:b4,rightshoulder:b5,back:b6,start:b7," +
+	"leftstick:b8,rightstick:b9,dpup:b10,dpright:b11,dpdown:b12,dpleft:b13," +
+	"leftx:a0,lefty:a1,rightx:a2,righty:a3,lefttrigger:a4,righttrigger:a5,")
+
+// AddGamepadMappings adds mappings in the SDL GameControllerDB format, one
+// per line. Lines for other platforms are skipped.
+//
+// GLFW 3.2 doesn't report joystick GUIDs, so mappings are matched by the
+// joystick name. It doesn't report hats either, hat elements (hN.M) are
+// never pressed.
+func AddGamepadMappings(db string) error {
+	scanner := bufio.NewScanner(strings.NewReader(db))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		m, platform, err := parseMapping(text)
+		if err != nil {
+			return fmt.Errorf("Invalid gamepad mapping at line %d: %v", line, err)
+		}
+		if platform != "" && platform != mappingPlatform() {
+			continue
+		}
+		gamepadMappings[m.name] = m
+	}
+	return scanner.Err()
+}
+
+func mappingPlatform() string {
+	switch runtime.GOOS {
+	case "windows":
+		return "Windows"
+	case "darwin":
+		return "Mac OS X"
+	default:
+		return "Linux"
+	}
+}
+
+func mustParseMapping(s string) *gamepadMapping {
+	m, _, err := parseMapping(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// parseMapping parses "GUID,name,target:source,...,platform:name,"
+func parseMapping(s string) (*gamepadMapping, string, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) < 2 {
+		return nil, "", fmt.Errorf("missing name")
+	}
+
+	m := &gamepadMapping{
+		name:     fields[1],
+		elements: make(map[mappingTarget]mappingSource),
+	}
+	platform := ""
+
+	for _, field := range fields[2:] {
+		if field == "" {
+			continue
+		}
+		colon := strings.Index(field, ":")
+		if colon < 0 {
+			return nil, "", fmt.Errorf("invalid element %q", field)
+		}
+		key, value := field[:colon], field[colon+1:]
+		if key == "platform" {
+			platform = value
+			continue
+		}
+
+		var target mappingTarget
+		if strings.HasPrefix(key, "+") || strings.HasPrefix(key, "-") {
+			target.half = halfOf(key[0])
+			key = key[1:]
+		}
+		if b, ok := mappingButtons[key]; ok {
+			target.button = b
+		} else if a, ok := mappingAxes[key]; ok {
+			target.axis, target.isAxis = a, true
+		} else {
+			// Unknown elements like touchpad or paddles are ignored
+			continue
+		}
+
+		source, err := parseMappingSource(value)
+		if err != nil {
+			return nil, "", fmt.Errorf("element %q: %v", field, err)
+		}
+		m.elements[target] = source
+	}
+	return m, platform, nil
+}
+
+func parseMappingSource(s string) (mappingSource, error) {
+	var source mappingSource
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		source.half = halfOf(s[0])
+		s = s[1:]
+	}
+	if strings.HasSuffix(s, "~") {
+		source.invert = true
+		s = s[:len(s)-1]
+	}
+	if len(s) < 2 {
+		return source, fmt.Errorf("invalid source %q", s)
+	}
+
+	source.kind = s[0]
+	switch source.kind {
+	case 'a', 'b':
+		index, err := strconv.Atoi(s[1:])
+		if err != nil {
+			return source, err
+		}
+		source.index = index
+	case 'h':
+		parts := strings.SplitN(s[1:], ".", 2)
+		if len(parts) != 2 {
+			return source, fmt.Errorf("invalid hat %q", s)
+		}
+		index, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return source, err
+		}
+		bit, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return source, err
+		}
+		source.index, source.hatBit = index, bit
+	default:
+		return source, fmt.Errorf("invalid source %q", s)
+	}
+	return source, nil
+}
+
+func halfOf(sign byte) int {
+	if sign == '-' {
+		return -1
+	}
+	return 1
+}
+
+// missing reports whether a source is an axis the joystick doesn't have
+func (s mappingSource) missing(axes []float32) bool {
+	return s.kind == 'a' && s.index >= len(axes)
+}
+
+// value reads a source as an axis value in [-1, 1], buttons are -1 when
+// released and 1 when pressed
+func (s mappingSource) value(axes []float32, buttons []byte) float32 {
+	v := float32(-1)
+	switch s.kind {
+	case 'a':
+		if s.index < len(axes) {
+			v = axes[s.index]
+		}
+	case 'b':
+		if s.index < len(buttons) && buttons[s.index] != 0 {
+			v = 1
+		}
+	}
+
+	if s.invert {
+		v = -v
+	}
+	// A half axis covers the full output range
+	switch s.half {
+	case 1:
+		v = v*2 - 1
+	case -1:
+		v = -v*2 - 1
+	}
+
+	if v < -1 {
+		return -1
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// apply reads the raw state of a joystick into the standard layout.
+// Targets mapped to axes the joystick doesn't have are left at rest.
+func (m *gamepadMapping) apply(axes []float32, buttons []byte) (b [gamepadButtonCount]bool, a [gamepadAxisCount]float32) {
+	for target, source := range m.elements {
+		if target.isAxis || source.missing(axes) {
+			continue
+		}
+		v := source.value(axes, buttons)
+		if source.kind == 'a' && source.half == 0 {
+			b[target.button] = v > 0.5
+		} else {
+			b[target.button] = v > 0
+		}
+	}
+
+	for target, source := range m.elements {
+		if target.isAxis && target.half == 0 && !source.missing(axes) {
+			v := source.value(axes, buttons)
+			if target.axis == GamepadLeftTrigger || target.axis == GamepadRightTrigger {
+				// Triggers are reported in [0, 1]
+				v = (v + 1) / 2
+			}
+			a[target.axis] = v
+		}
+	}
+
+	// Buttons or half axes driving one direction of an axis are added last
+	// so that they combine with a full axis mapped to the same output
+	for target, source := range m.elements {
+		if target.isAxis && target.half != 0 && !source.missing(axes) {
+			v := source.value(axes, buttons)
+			a[target.axis] += float32(target.half) * (v + 1) / 2
+		}
+	}
+	return b, a
+}
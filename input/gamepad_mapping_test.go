@@ -0,0 +1,41 @@
+package input
+
+import "testing"
+
+func TestMissingAxesAtRest(t *testing.T) {
+	// A pad with a single stick and no triggers falling back to XInput
+	axes := []float32{0.25, -1, 0, 0}
+	buttons := []byte{1, 0}
+	b, a := xinputMapping.apply(axes, buttons)
+
+	want := [gamepadAxisCount]float32{
+		GamepadLeftX:        0.25,
+		GamepadLeftY:        -1,
+		GamepadLeftTrigger:  0,
+		GamepadRightTrigger: 0,
+	}
+	if a != want {
+		t.Errorf("axes are %v, want %v", a, want)
+	}
+	if !b[GamepadA] || b[GamepadB] || b[GamepadDPadUp] {
+		t.Errorf("buttons are %v, want only A pressed", b)
+	}
+
+	tests := []struct {
+		name string
+		axes []float32
+		want [gamepadAxisCount]float32
+	}{
+		{"no axes", nil, [gamepadAxisCount]float32{}},
+		{"triggers released", []float32{0, 0, 0, 0, -1, -1}, [gamepadAxisCount]float32{}},
+		{"triggers pressed", []float32{0, 0, 0, 0, 1, 0}, [gamepadAxisCount]float32{
+			GamepadLeftTrigger:  1,
+			GamepadRightTrigger: 0.5,
+		}},
+	}
+	for _, tt := range tests {
+		if _, got := xinputMapping.apply(tt.axes, nil); got != tt.want {
+			t.Errorf("%v: axes are %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -6,10 +6,193 @@ import (
 	"kiwanoengine.com/kiwano"
 )
 
-// Pressed ...
+// ButtonAction is the state change of a key or button reported by an event
+type ButtonAction int
+
+const (
+	Release = ButtonAction(glfw.Release)
+	Press   = ButtonAction(glfw.Press)
+	Repeat  = ButtonAction(glfw.Repeat)
+)
+
+// ModifierKey is a set of modifier keys held during an input event
+type ModifierKey int
+
+const (
+	ModShift   = ModifierKey(glfw.ModShift)
+	ModControl = ModifierKey(glfw.ModControl)
+	ModAlt     = ModifierKey(glfw.ModAlt)
+	ModSuper   = ModifierKey(glfw.ModSuper)
+)
+
+// KeyEvent is sent when a key is pressed, repeated or released
+type KeyEvent struct {
+	Key      Key
+	Scancode int
+	Action   ButtonAction
+	Mods     ModifierKey
+}
+
+// CharEvent is sent when a character is typed, for text input
+type CharEvent struct {
+	Char rune
+}
+
+const keyCount = int(glfw.KeyLast) + 1
+
+var keyboard struct {
+	down     [keyCount]bool
+	pressed  [keyCount]bool
+	released [keyCount]bool
+	repeated [keyCount]bool
+	text     []rune
+}
+
+var (
+	keyListeners  []*Listener
+	charListeners []*Listener
+)
+
+func init() {
+	kiwano.AddInputHandler(handler{})
+}
+
+// Pressed reports whether a key is held down
 func Pressed(key Key) bool {
-	if kiwano.MainWindow == nil {
-		return false
+	return validKey(key) && keyboard.down[key]
+}
+
+// JustPressed reports whether a key was pressed since the last frame
+func JustPressed(key Key) bool {
+	return validKey(key) && keyboard.pressed[key]
+}
+
+// JustReleased reports whether a key was released since the last frame
+func JustReleased(key Key) bool {
+	return validKey(key) && keyboard.released[key]
+}
+
+// Repeated reports whether a held key was repeated by the system since the
+// last frame
+func Repeated(key Key) bool {
+	return validKey(key) && keyboard.repeated[key]
+}
+
+// Text returns the characters typed since the last frame
+func Text() string {
+	return string(keyboard.text)
+}
+
+func validKey(key Key) bool {
+	return key >= 0 && int(key) < keyCount
+}
+
+// Listener is a function subscribed to input events
+type Listener struct {
+	fn      interface{}
+	removed bool
+}
+
+// Remove unsubscribes the listener
+func (l *Listener) Remove() {
+	l.removed = true
+}
+
+// OnKey subscribes a function to key events
+func OnKey(fn func(KeyEvent)) *Listener {
+	l := &Listener{fn: fn}
+	keyListeners = append(keyListeners, l)
+	return l
+}
+
+// OnChar subscribes a function to character events
+func OnChar(fn func(CharEvent)) *Listener {
+	l := &Listener{fn: fn}
+	charListeners = append(charListeners, l)
+	return l
+}
+
+// dispatch calls the listeners which are not removed and drops the others
+func dispatch(listeners *[]*Listener, call func(fn interface{})) {
+	active := (*listeners)[:0]
+	for _, l := range *listeners {
+		if !l.removed {
+			active = append(active, l)
+		}
+	}
+	*listeners = active
+
+	// Iterate over a copy, listeners may subscribe other listeners
+	for _, l := range append([]*Listener(nil), active...) {
+		if !l.removed {
+			call(l.fn)
+		}
+	}
+}
+
+// handler receives the events of the main window
+type handler struct{}
+
+func (handler) NewFrame() {
+	newActionsFrame()
+	keyboard.pressed = [keyCount]bool{}
+	keyboard.released = [keyCount]bool{}
+	keyboard.repeated = [keyCount]bool{}
+	keyboard.text = keyboard.text[:0]
+	newMouseFrame()
+	pollJoysticks()
+}
+
+func (handler) OnKey(key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+	live(RecordedEvent{Kind: KeyEventKind, Key: KeyEvent{Key(key), scancode, ButtonAction(action), ModifierKey(mods)}})
+}
+
+func (handler) OnChar(char rune) {
+	live(RecordedEvent{Kind: CharEventKind, Char: CharEvent{char}})
+}
+
+func (handler) OnMouseButton(button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+	live(RecordedEvent{Kind: MouseButtonEventKind, MouseButton: MouseButtonEvent{
+		MouseButton(button), ButtonAction(action), ModifierKey(mods), mouse.x, mouse.y,
+	}})
+}
+
+func (handler) OnCursorPos(x, y float64) {
+	live(RecordedEvent{Kind: CursorEventKind, Cursor: CursorEvent{x, y, mouse.inWindow}})
+}
+
+func (handler) OnCursorEnter(entered bool) {
+	live(RecordedEvent{Kind: CursorEventKind, Cursor: CursorEvent{mouse.x, mouse.y, entered}})
+}
+
+func (handler) OnScroll(xoff, yoff float64) {
+	live(RecordedEvent{Kind: ScrollEventKind, Scroll: ScrollEvent{xoff, yoff}})
+}
+
+func handleKey(e KeyEvent) {
+	if validKey(e.Key) {
+		switch e.Action {
+		case Press:
+			keyboard.down[e.Key] = true
+			keyboard.pressed[e.Key] = true
+			captured(KeyBinding(e.Key))
+		case Release:
+			keyboard.down[e.Key] = false
+			keyboard.released[e.Key] = true
+		case Repeat:
+			keyboard.repeated[e.Key] = true
+		}
 	}
-	return kiwano.MainWindow.GetKey(glfw.Key(key)) == glfw.Press
+
+	dispatch(&keyListeners, func(fn interface{}) {
+		fn.(func(KeyEvent))(e)
+	})
+}
+
+func handleChar(e CharEvent) {
+	keyboard.text = append(keyboard.text, e.Char)
+
+	dispatch(&charListeners, func(fn interface{}) {
+		fn.(func(CharEvent))(e)
+	})
 }
@@ -0,0 +1,255 @@
+package input
+
+import (
+	"image"
+
+	"github.com/go-gl/glfw/v3.2/glfw"
+
+	"kiwanoengine.com/kiwano"
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// MouseButton corresponds to a mouse button.
+type MouseButton int
+
+const (
+	MouseLeft   = MouseButton(glfw.MouseButtonLeft)
+	MouseRight  = MouseButton(glfw.MouseButtonRight)
+	MouseMiddle = MouseButton(glfw.MouseButtonMiddle)
+	Mouse4      = MouseButton(glfw.MouseButton4)
+	Mouse5      = MouseButton(glfw.MouseButton5)
+	Mouse6      = MouseButton(glfw.MouseButton6)
+	Mouse7      = MouseButton(glfw.MouseButton7)
+	Mouse8      = MouseButton(glfw.MouseButton8)
+)
+
+// CursorMode controls the visibility and movement of the cursor
+type CursorMode int
+
+const (
+	// CursorNormal shows the cursor
+	CursorNormal = CursorMode(glfw.CursorNormal)
+	// CursorHidden hides the cursor while it is over the window
+	CursorHidden = CursorMode(glfw.CursorHidden)
+	// CursorLocked hides the cursor and locks it to the window, the cursor
+	// position keeps changing for camera controls
+	CursorLocked = CursorMode(glfw.CursorDisabled)
+)
+
+// StandardCursor is a cursor shape provided by the system
+type StandardCursor int
+
+const (
+	ArrowCursor     = StandardCursor(glfw.ArrowCursor)
+	IBeamCursor     = StandardCursor(glfw.IBeamCursor)
+	CrosshairCursor = StandardCursor(glfw.CrosshairCursor)
+	HandCursor      = StandardCursor(glfw.HandCursor)
+	HResizeCursor   = StandardCursor(glfw.HResizeCursor)
+	VResizeCursor   = StandardCursor(glfw.VResizeCursor)
+)
+
+// MouseButtonEvent is sent when a mouse button is pressed or released
+type MouseButtonEvent struct {
+	Button MouseButton
+	Action ButtonAction
+	Mods   ModifierKey
+	// X and Y are the cursor position in window coordinates
+	X, Y float64
+}
+
+// CursorEvent is sent when the cursor moves, enters or leaves the window
+type CursorEvent struct {
+	// X and Y are the cursor position in window coordinates
+	X, Y    float64
+	Entered bool
+}
+
+// ScrollEvent is sent when the mouse wheel or the touchpad scrolls
+type ScrollEvent struct {
+	X, Y float64
+}
+
+const mouseButtonCount = int(glfw.MouseButtonLast) + 1
+
+var mouse struct {
+	down     [mouseButtonCount]bool
+	pressed  [mouseButtonCount]bool
+	released [mouseButtonCount]bool
+
+	x, y             float64
+	lastX, lastY     float64
+	scrollX, scrollY float64
+	inWindow         bool
+}
+
+var (
+	mouseButtonListeners []*Listener
+	cursorListeners      []*Listener
+	scrollListeners      []*Listener
+)
+
+// MousePressed reports whether a mouse button is held down
+func MousePressed(button MouseButton) bool {
+	return validMouseButton(button) && mouse.down[button]
+}
+
+// MouseJustPressed reports whether a mouse button was pressed since the
+// last frame
+func MouseJustPressed(button MouseButton) bool {
+	return validMouseButton(button) && mouse.pressed[button]
+}
+
+// MouseJustReleased reports whether a mouse button was released since the
+// last frame
+func MouseJustReleased(button MouseButton) bool {
+	return validMouseButton(button) && mouse.released[button]
+}
+
+func validMouseButton(button MouseButton) bool {
+	return button >= 0 && int(button) < mouseButtonCount
+}
+
+// CursorPos returns the cursor position in window coordinates
+func CursorPos() (x, y float64) {
+	return mouse.x, mouse.y
+}
+
+// CursorWorldPos returns the cursor position in world coordinates, through
+// the camera of the current scene
+func CursorWorldPos() geom.Vec2 {
+	if kiwano.MainWindow == nil {
+		return geom.Vec2{}
+	}
+	return kiwano.MainWindow.ScreenToWorld(mouse.x, mouse.y)
+}
+
+// CursorViewPos returns the cursor position in view coordinates, which
+// ignore cameras
+func CursorViewPos() geom.Vec2 {
+	if kiwano.MainWindow == nil {
+		return geom.Vec2{}
+	}
+	return kiwano.MainWindow.ScreenToView(mouse.x, mouse.y)
+}
+
+// CursorDelta returns how much the cursor moved since the last frame, in
+// window coordinates
+func CursorDelta() (x, y float64) {
+	return mouse.x - mouse.lastX, mouse.y - mouse.lastY
+}
+
+// CursorInWindow reports whether the cursor is over the window
+func CursorInWindow() bool {
+	return mouse.inWindow
+}
+
+// ScrollDelta returns the scroll offset accumulated since the last frame
+func ScrollDelta() (x, y float64) {
+	return mouse.scrollX, mouse.scrollY
+}
+
+// OnMouseButton subscribes a function to mouse button events
+func OnMouseButton(fn func(MouseButtonEvent)) *Listener {
+	l := &Listener{fn: fn}
+	mouseButtonListeners = append(mouseButtonListeners, l)
+	return l
+}
+
+// OnCursor subscribes a function to cursor move, enter and leave events
+func OnCursor(fn func(CursorEvent)) *Listener {
+	l := &Listener{fn: fn}
+	cursorListeners = append(cursorListeners, l)
+	return l
+}
+
+// OnScroll subscribes a function to scroll events
+func OnScroll(fn func(ScrollEvent)) *Listener {
+	l := &Listener{fn: fn}
+	scrollListeners = append(scrollListeners, l)
+	return l
+}
+
+// SetCursorMode changes the visibility and movement of the cursor
+func SetCursorMode(mode CursorMode) {
+	if w := kiwano.MainWindow; w != nil && !w.Headless() {
+		w.SetInputMode(glfw.CursorMode, int(mode))
+	}
+}
+
+// Cursor is a custom cursor image
+type Cursor struct {
+	cursor *glfw.Cursor
+}
+
+// NewCursor creates a cursor from an image, (hotX, hotY) is the pixel
+// of the image pointing at the cursor position
+func NewCursor(img image.Image, hotX, hotY int) *Cursor {
+	return &Cursor{glfw.CreateCursor(img, hotX, hotY)}
+}
+
+// NewStandardCursor creates a cursor with a shape provided by the system
+func NewStandardCursor(shape StandardCursor) *Cursor {
+	return &Cursor{glfw.CreateStandardCursor(glfw.StandardCursor(shape))}
+}
+
+// Destroy deletes the cursor, it must not be in use
+func (c *Cursor) Destroy() {
+	c.cursor.Destroy()
+}
+
+// SetCursor changes the cursor shown over the window, nil restores the
+// default arrow
+func SetCursor(c *Cursor) {
+	w := kiwano.MainWindow
+	if w == nil || w.Headless() {
+		return
+	}
+	if c == nil {
+		w.SetCursor(nil)
+	} else {
+		w.SetCursor(c.cursor)
+	}
+}
+
+func newMouseFrame() {
+	mouse.pressed = [mouseButtonCount]bool{}
+	mouse.released = [mouseButtonCount]bool{}
+	mouse.lastX, mouse.lastY = mouse.x, mouse.y
+	mouse.scrollX, mouse.scrollY = 0, 0
+}
+
+func handleMouseButton(e MouseButtonEvent) {
+	if validMouseButton(e.Button) {
+		switch e.Action {
+		case Press:
+			mouse.down[e.Button] = true
+			mouse.pressed[e.Button] = true
+			captured(MouseBinding(e.Button))
+		case Release:
+			mouse.down[e.Button] = false
+			mouse.released[e.Button] = true
+		}
+	}
+
+	dispatch(&mouseButtonListeners, func(fn interface{}) {
+		fn.(func(MouseButtonEvent))(e)
+	})
+}
+
+func handleCursor(e CursorEvent) {
+	mouse.x, mouse.y = e.X, e.Y
+	mouse.inWindow = e.Entered
+
+	dispatch(&cursorListeners, func(fn interface{}) {
+		fn.(func(CursorEvent))(e)
+	})
+}
+
+func handleScroll(e ScrollEvent) {
+	mouse.scrollX += e.X
+	mouse.scrollY += e.Y
+
+	dispatch(&scrollListeners, func(fn interface{}) {
+		fn.(func(ScrollEvent))(e)
+	})
+}
@@ -0,0 +1,358 @@
+package input
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"kiwanoengine.com/kiwano"
+	"kiwanoengine.com/kiwano/node"
+)
+
+// recordingVersion is increased when the file format changes
+const recordingVersion = 1
+
+// EventKind is the type of a recorded input event
+type EventKind int
+
+const (
+	KeyEventKind EventKind = iota
+	CharEventKind
+	MouseButtonEventKind
+	CursorEventKind
+	ScrollEventKind
+	JoystickEventKind
+)
+
+// JoystickState is the raw state of a joystick before gamepad mapping
+type JoystickState struct {
+	Present bool
+	Name    string
+	Axes    []float32
+	Buttons []byte
+}
+
+func (s *JoystickState) equal(o *JoystickState) bool {
+	if s.Present != o.Present || s.Name != o.Name ||
+		len(s.Axes) != len(o.Axes) || len(s.Buttons) != len(o.Buttons) {
+		return false
+	}
+	for i := range s.Axes {
+		if s.Axes[i] != o.Axes[i] {
+			return false
+		}
+	}
+	for i := range s.Buttons {
+		if s.Buttons[i] != o.Buttons[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// RecordedEvent is an input event, only the field matching Kind is set
+type RecordedEvent struct {
+	Kind        EventKind
+	Key         KeyEvent
+	Char        CharEvent
+	MouseButton MouseButtonEvent
+	Cursor      CursorEvent
+	Scroll      ScrollEvent
+	Gamepad     Gamepad
+	Joystick    JoystickState
+}
+
+// apply feeds the event to the input state and the listeners
+func (e *RecordedEvent) apply() {
+	switch e.Kind {
+	case KeyEventKind:
+		handleKey(e.Key)
+	case CharEventKind:
+		handleChar(e.Char)
+	case MouseButtonEventKind:
+		handleMouseButton(e.MouseButton)
+	case CursorEventKind:
+		handleCursor(e.Cursor)
+	case ScrollEventKind:
+		handleScroll(e.Scroll)
+	case JoystickEventKind:
+		if e.Gamepad >= 0 && int(e.Gamepad) < MaxGamepads {
+			joysticks[e.Gamepad] = e.Joystick
+			updateJoystick(e.Gamepad)
+		}
+	}
+}
+
+// RecordedFrame holds the events received before a frame and its delta
+type RecordedFrame struct {
+	Delta  time.Duration
+	Events []RecordedEvent
+}
+
+// Recording is a sequence of frames captured by StartRecording
+type Recording struct {
+	Frames []RecordedFrame
+	// Seed seeds node.SeedRandom when the recording starts and when it is
+	// replayed, so that camera shakes repeat
+	Seed int64
+}
+
+// Duration returns the total time of the recorded frames
+func (r *Recording) Duration() time.Duration {
+	var d time.Duration
+	for _, f := range r.Frames {
+		d += f.Delta
+	}
+	return d
+}
+
+// Save writes the recording to a file
+func (r *Recording) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	enc := gob.NewEncoder(file)
+	if err := enc.Encode(recordingVersion); err != nil {
+		file.Close()
+		return err
+	}
+	if err := enc.Encode(r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LoadRecording reads a recording written by Recording.Save
+func LoadRecording(path string) (*Recording, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dec := gob.NewDecoder(file)
+	var version int
+	if err := dec.Decode(&version); err != nil {
+		return nil, err
+	}
+	if version != recordingVersion {
+		return nil, fmt.Errorf("Unsupported recording version %v in %v", version, path)
+	}
+
+	r := &Recording{}
+	if err := dec.Decode(r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+var (
+	// recorder is the recording in progress and pending the events of the
+	// frame being recorded
+	recorder *Recording
+	pending  []RecordedEvent
+
+	// replaying is the recording being replayed, next is its next frame
+	replaying  *Recording
+	replayNext int
+	replayDone func()
+
+	// joysticks is the last raw state of the joysticks, read from GLFW or
+	// from the recording being replayed
+	joysticks [MaxGamepads]JoystickState
+)
+
+func init() {
+	kiwano.SetDeltaHook(frameDelta)
+}
+
+// StartRecording records the input events and the frame deltas of
+// kiwano.RunFrame until StopRecording is called. Keys and buttons held when
+// the recording starts are recorded as pressed in the first frame.
+func StartRecording() error {
+	if replaying != nil {
+		return errors.New("Cannot record input during a replay")
+	}
+
+	recorder = &Recording{Seed: time.Now().UnixNano()}
+	pending = nil
+	node.SeedRandom(recorder.Seed)
+	for key, down := range keyboard.down {
+		if down {
+			record(RecordedEvent{Kind: KeyEventKind, Key: KeyEvent{Key: Key(key), Action: Press}})
+		}
+	}
+	record(RecordedEvent{Kind: CursorEventKind, Cursor: CursorEvent{mouse.x, mouse.y, mouse.inWindow}})
+	for button, down := range mouse.down {
+		if down {
+			record(RecordedEvent{Kind: MouseButtonEventKind, MouseButton: MouseButtonEvent{
+				Button: MouseButton(button), Action: Press, X: mouse.x, Y: mouse.y,
+			}})
+		}
+	}
+	for g := range joysticks {
+		if joysticks[g].Present {
+			record(RecordedEvent{Kind: JoystickEventKind, Gamepad: Gamepad(g), Joystick: joysticks[g]})
+		}
+	}
+	return nil
+}
+
+// StopRecording ends the recording and returns it. The events received
+// after the last frame are dropped.
+func StopRecording() *Recording {
+	r := recorder
+	recorder = nil
+	pending = nil
+	return r
+}
+
+// IsRecording reports whether the input is being recorded
+func IsRecording() bool {
+	return recorder != nil
+}
+
+// Replay feeds a recording to the game instead of the live input,
+// kiwano.RunFrame and MainLoop use the recorded frame deltas so that the
+// game sees identical input. Replays start from a released keyboard, mouse
+// and gamepads and work with headless windows. done, which may be nil, is
+// called when the last frame starts, calling kiwano.Exit from it ends
+// MainLoop after that frame. A recording without frames ends at once.
+func Replay(r *Recording, done func()) {
+	recorder = nil
+	pending = nil
+	replaying = r
+	replayNext = 0
+	replayDone = done
+	resetInput()
+	node.SeedRandom(r.Seed)
+
+	if len(r.Frames) == 0 {
+		finishReplay()
+	}
+}
+
+// StopReplay ends the replay in progress and returns to the live input
+func StopReplay() {
+	replaying = nil
+	replayDone = nil
+	resetInput()
+}
+
+// IsReplaying reports whether a recording is being replayed
+func IsReplaying() bool {
+	return replaying != nil
+}
+
+// resetInput releases all the keys, buttons and gamepads
+func resetInput() {
+	keyboard.down = [keyCount]bool{}
+	keyboard.pressed = [keyCount]bool{}
+	keyboard.released = [keyCount]bool{}
+	keyboard.repeated = [keyCount]bool{}
+	keyboard.text = keyboard.text[:0]
+
+	mouse.down = [mouseButtonCount]bool{}
+	mouse.pressed = [mouseButtonCount]bool{}
+	mouse.released = [mouseButtonCount]bool{}
+	mouse.x, mouse.y, mouse.lastX, mouse.lastY = 0, 0, 0, 0
+	mouse.scrollX, mouse.scrollY = 0, 0
+	mouse.inWindow = false
+
+	for g := range gamepads {
+		joysticks[g] = JoystickState{}
+		updateJoystick(Gamepad(g))
+		gamepads[g].prevButtons = gamepads[g].buttons
+	}
+	for _, a := range actions {
+		a.wasDown = false
+	}
+}
+
+// live handles an event received from the window, which is ignored during
+// a replay
+func live(e RecordedEvent) {
+	if replaying != nil {
+		return
+	}
+	record(e)
+	e.apply()
+}
+
+func record(e RecordedEvent) {
+	if recorder != nil {
+		pending = append(pending, e)
+	}
+}
+
+// frameDelta is called by kiwano.RunFrame before each frame, it closes the
+// recorded frame or applies the events of the replayed one
+func frameDelta(dt time.Duration) time.Duration {
+	if recorder != nil {
+		recorder.Frames = append(recorder.Frames, RecordedFrame{dt, pending})
+		pending = nil
+	}
+
+	if replaying == nil {
+		return dt
+	}
+
+	r := replaying
+	if replayNext >= len(r.Frames) {
+		finishReplay()
+		return dt
+	}
+	frame := r.Frames[replayNext]
+	replayNext++
+	for i := range frame.Events {
+		frame.Events[i].apply()
+	}
+
+	if replayNext == len(r.Frames) {
+		finishReplay()
+	}
+	return frame.Delta
+}
+
+// finishReplay returns to the live input and calls the done function
+func finishReplay() {
+	done := replayDone
+	replaying = nil
+	replayDone = nil
+	if done != nil {
+		done()
+	}
+}
+
+// pollJoysticks reads the raw state of the joysticks from GLFW, which 3.2
+// can only report by polling, or keeps the replayed state during a replay
+func pollJoysticks() {
+	if replaying != nil {
+		for g := range joysticks {
+			updateJoystick(Gamepad(g))
+		}
+		return
+	}
+	if w := kiwano.MainWindow; w == nil || w.Headless() {
+		return
+	}
+
+	for g := range joysticks {
+		state := readJoystick(Gamepad(g))
+		if !state.equal(&joysticks[g]) {
+			record(RecordedEvent{Kind: JoystickEventKind, Gamepad: Gamepad(g), Joystick: state})
+		}
+		joysticks[g] = state
+		updateJoystick(Gamepad(g))
+	}
+}
+
+func updateJoystick(g Gamepad) {
+	s := &joysticks[g]
+	updateGamepad(g, s.Present, s.Name, s.Axes, s.Buttons)
+}
@@ -1,11 +1,9 @@
 package kiwano
 
 import (
-	"log"
 	"runtime"
 	"time"
 
-	"kiwanoengine.com/kiwano/external/gl"
 	"kiwanoengine.com/kiwano/render"
 
 	"github.com/go-gl/glfw/v3.2/glfw"
@@ -53,7 +51,9 @@ func Init(option *Option) error {
 		return err
 	}
 
-	log.Println("OpenGL version", gl.GetString(gl.VERSION))
+	SetTickRate(option.TickRate)
+	SetMaxFixedSteps(option.MaxFixedSteps)
+	SetTargetFPS(option.TargetFPS)
 
 	MainWindow.Show()
 	return nil
@@ -65,29 +65,72 @@ func MainLoop() {
 	last := now
 
 	for !MainWindow.ShouldClose() {
-		// render
-		gl.Clear(gl.COLOR_BUFFER_BIT)
-
 		now = time.Now()
-		if CurrentScene != nil {
-			CurrentScene.OnUpdate(now.Sub(last))
-		}
+		dt := now.Sub(last)
 		last = now
+		RunFrame(dt)
 
-		// swap buffer
-		MainWindow.SwapBuffers()
-		glfw.PollEvents()
+		if !MainWindow.Headless() {
+			glfw.PollEvents()
+		}
+		limitFrameRate(now)
 	}
 
 	// Clear current scene
 	EnterScene(nil)
 }
 
+// RunFrame runs one frame like MainLoop without polling window events: dt
+// goes through the delta hook, which replays recorded input, then Step
+// runs and a new input frame starts. Headless programs and tests replaying
+// input call it instead of Step.
+func RunFrame(dt time.Duration) {
+	if deltaHook != nil {
+		dt = deltaHook(dt)
+	}
+	Step(dt)
+	newInputFrame()
+}
+
+// Step updates the current scene by dt and renders one frame. Fixed
+// updates due in dt run first, then OnUpdate and the node tree, then the
+// visible scenes of the stack are rendered. dt is scaled by the time scale
+// and updates are skipped while paused. MainLoop calls it with the
+// wall-clock time between frames, headless programs and tests can call it
+// directly with a fixed delta.
+func Step(dt time.Duration) {
+	frameDelta := dt
+	dt = tick(dt)
+
+	if CurrentScene != nil && !paused {
+		updateScene(CurrentScene, dt)
+	}
+
+	// render
+	render.Clear(MainWindow.ClearColor)
+	render.BeginFrame()
+
+	if transition != nil {
+		renderTransition(frameDelta)
+	} else {
+		renderScenes(visibleScenes(), 1)
+	}
+	render.EndFrame()
+
+	// swap buffer
+	MainWindow.SwapBuffers()
+}
+
 // Destroy clean up engine resources
 func Destroy() {
+	render.DestroyAllBatches()
+	render.DestroyAllRenderTargets()
 	render.DestroyAllShaders()
+	render.DestroyAllTextures()
 	MainWindow.Destroy()
-	glfw.Terminate()
+	if !MainWindow.Headless() {
+		glfw.Terminate()
+	}
 }
 
 // Exit stop the main loop
@@ -96,15 +139,3 @@ func Exit() {
 		MainWindow.SetShouldClose(true)
 	}
 }
-
-// EnterScene exits current scene and enters a new scene
-func EnterScene(scene Scene) {
-	if CurrentScene != nil {
-		CurrentScene.OnExit()
-	}
-
-	CurrentScene = scene
-	if CurrentScene != nil {
-		CurrentScene.OnEnter()
-	}
-}
@@ -0,0 +1,70 @@
+package kiwano
+
+import "github.com/go-gl/glfw/v3.2/glfw"
+
+// VideoMode is a resolution and refresh rate supported by a monitor
+type VideoMode struct {
+	Width, Height int
+	RefreshRate   int
+}
+
+// Monitor is a display connected to the computer
+type Monitor struct {
+	monitor *glfw.Monitor
+}
+
+// Monitors returns the connected monitors, the primary monitor first. It
+// returns nil for headless windows.
+func Monitors() []*Monitor {
+	if MainWindow == nil || MainWindow.Headless() {
+		return nil
+	}
+	var monitors []*Monitor
+	for _, m := range glfw.GetMonitors() {
+		monitors = append(monitors, &Monitor{m})
+	}
+	return monitors
+}
+
+// PrimaryMonitor returns the main display, or nil for headless windows
+func PrimaryMonitor() *Monitor {
+	if MainWindow == nil || MainWindow.Headless() {
+		return nil
+	}
+	if m := glfw.GetPrimaryMonitor(); m != nil {
+		return &Monitor{m}
+	}
+	return nil
+}
+
+// Name returns the human-readable name of the monitor
+func (m *Monitor) Name() string {
+	return m.monitor.GetName()
+}
+
+// Position returns the position of the monitor on the virtual desktop
+func (m *Monitor) Position() (x, y int) {
+	return m.monitor.GetPos()
+}
+
+// VideoMode returns the current video mode of the monitor
+func (m *Monitor) VideoMode() VideoMode {
+	return toVideoMode(m.monitor.GetVideoMode())
+}
+
+// VideoModes returns the video modes supported by the monitor, from the
+// smallest to the largest
+func (m *Monitor) VideoModes() []VideoMode {
+	var modes []VideoMode
+	for _, mode := range m.monitor.GetVideoModes() {
+		modes = append(modes, toVideoMode(mode))
+	}
+	return modes
+}
+
+func toVideoMode(mode *glfw.VidMode) VideoMode {
+	if mode == nil {
+		return VideoMode{}
+	}
+	return VideoMode{mode.Width, mode.Height, mode.RefreshRate}
+}
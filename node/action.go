@@ -0,0 +1,82 @@
+package node
+
+import "time"
+
+// Action is a task run by a node every frame before OnUpdate, like a tween
+// or a timer. Actions only run while the node is updated, so they pause
+// with the scene and follow its time scale.
+type Action interface {
+	// Step advances the action by dt and reports whether it is finished
+	Step(dt time.Duration) bool
+}
+
+// Stopper is implemented by actions which need to know when they are
+// stopped before finishing, by StopAction or when their node is removed
+type Stopper interface {
+	OnStop()
+}
+
+type runningAction struct {
+	action  Action
+	stopped bool
+}
+
+func (r *runningAction) stop() {
+	r.stopped = true
+	if s, ok := r.action.(Stopper); ok {
+		s.OnStop()
+	}
+}
+
+// RunAction starts running an action on the node. Actions are stopped when
+// the node is removed from its parent.
+func (n *NodeProperties) RunAction(a Action) {
+	n.actions = append(n.actions, &runningAction{action: a})
+}
+
+// StopAction stops an action running on the node
+func (n *NodeProperties) StopAction(a Action) {
+	for i, r := range n.actions {
+		if r.action == a {
+			r.stop()
+			n.removeAction(i)
+			return
+		}
+	}
+}
+
+// StopAllActions stops all the actions running on the node
+func (n *NodeProperties) StopAllActions() {
+	// Clear the list first so that OnStop can run new actions
+	actions := n.actions
+	n.actions = nil
+	for _, r := range actions {
+		r.stop()
+	}
+}
+
+// NumActions returns the number of actions running on the node
+func (n *NodeProperties) NumActions() int {
+	return len(n.actions)
+}
+
+func (n *NodeProperties) removeAction(i int) {
+	n.actions[i].stopped = true
+	// Build a new slice so that runActions is not affected
+	n.actions = append(n.actions[:i:i], n.actions[i+1:]...)
+}
+
+func (n *NodeProperties) runActions(dt time.Duration) {
+	for _, r := range n.actions {
+		// Skip actions stopped by a previous action during this step
+		if r.stopped || !r.action.Step(dt) {
+			continue
+		}
+		for i := range n.actions {
+			if n.actions[i] == r {
+				n.removeAction(i)
+				break
+			}
+		}
+	}
+}
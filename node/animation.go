@@ -0,0 +1,313 @@
+package node
+
+import (
+	"fmt"
+	"time"
+
+	"kiwanoengine.com/kiwano/render"
+)
+
+// PlayMode controls what an animation does after its last frame
+type PlayMode int
+
+const (
+	// PlayLoop restarts from the first frame
+	PlayLoop PlayMode = iota
+	// PlayPingPong plays the frames backwards, then forwards again
+	PlayPingPong
+	// PlayOnce stops on the last frame
+	PlayOnce
+)
+
+// DefaultFrameDuration is the display time of frames without a duration,
+// like the frames of atlases not exported by Aseprite
+const DefaultFrameDuration = 100 * time.Millisecond
+
+// AnimationFrame is a frame of an animation with its display time
+type AnimationFrame struct {
+	Frame    *render.Frame
+	Duration time.Duration
+}
+
+// Animation is a named sequence of frames
+type Animation struct {
+	Name   string
+	Frames []AnimationFrame
+	Mode   PlayMode
+	// Loops is the number of cycles played by PlayLoop and PlayPingPong
+	// animations before they complete, 0 means forever
+	Loops int
+}
+
+// NewAnimation creates an animation showing every frame for the same time
+func NewAnimation(name string, frames []*render.Frame, frameDuration time.Duration, mode PlayMode) *Animation {
+	a := &Animation{
+		Name: name,
+		Mode: mode,
+	}
+	for _, f := range frames {
+		a.Frames = append(a.Frames, AnimationFrame{f, frameDuration})
+	}
+	return a
+}
+
+// NewAnimationFromAtlas creates an animation from frames of an atlas
+func NewAnimationFromAtlas(name string, atlas *render.Atlas, frameNames []string,
+	frameDuration time.Duration, mode PlayMode) (*Animation, error) {
+
+	frames := make([]*render.Frame, len(frameNames))
+	for i, frameName := range frameNames {
+		if frames[i] = atlas.Frame(frameName); frames[i] == nil {
+			return nil, fmt.Errorf("Frame %v not found in atlas", frameName)
+		}
+	}
+	return NewAnimation(name, frames, frameDuration, mode), nil
+}
+
+// LoadAseprite loads a JSON file exported by Aseprite, with one animation
+// per tag using the frame durations of the file
+func LoadAseprite(path string) (*render.Atlas, map[string]*Animation, error) {
+	atlas, err := render.LoadAtlas(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return atlas, AnimationsFromTags(atlas), nil
+}
+
+// AnimationsFromTags creates an animation for every tag of an atlas, frames
+// without a duration are shown for DefaultFrameDuration
+func AnimationsFromTags(atlas *render.Atlas) map[string]*Animation {
+	frames := atlas.Frames()
+	animations := make(map[string]*Animation)
+
+	for _, tag := range atlas.Tags() {
+		a := &Animation{
+			Name:  tag.Name,
+			Mode:  PlayLoop,
+			Loops: tag.Repeat,
+		}
+		for _, f := range frames[tag.From : tag.To+1] {
+			duration := f.Duration
+			if duration <= 0 {
+				duration = DefaultFrameDuration
+			}
+			a.Frames = append(a.Frames, AnimationFrame{f, duration})
+		}
+
+		switch tag.Direction {
+		case "reverse":
+			a.reverse()
+		case "pingpong":
+			a.Mode = PlayPingPong
+		case "pingpong_reverse":
+			a.reverse()
+			a.Mode = PlayPingPong
+		}
+		animations[tag.Name] = a
+	}
+	return animations
+}
+
+func (a *Animation) reverse() {
+	for i, j := 0, len(a.Frames)-1; i < j; i, j = i+1, j-1 {
+		a.Frames[i], a.Frames[j] = a.Frames[j], a.Frames[i]
+	}
+}
+
+// AnimatedSprite is a sprite playing frame animations
+type AnimatedSprite struct {
+	Sprite
+
+	animations map[string]*Animation
+	current    *Animation
+	index      int
+	backwards  bool
+	loops      int
+	elapsed    time.Duration
+	speed      float64
+	playing    bool
+
+	onFrame    func(index int)
+	onComplete func(name string)
+}
+
+// NewAnimatedSprite creates an animated sprite without animations
+func NewAnimatedSprite() *AnimatedSprite {
+	return &AnimatedSprite{
+		Sprite: Sprite{
+			color: render.White,
+		},
+		animations: make(map[string]*Animation),
+		speed:      1,
+	}
+}
+
+// AddAnimation registers an animation which can be played by name
+func (s *AnimatedSprite) AddAnimation(a *Animation) {
+	s.animations[a.Name] = a
+}
+
+// Animation returns the registered animation with the given name
+func (s *AnimatedSprite) Animation(name string) *Animation {
+	return s.animations[name]
+}
+
+// Play starts an animation from its first frame
+func (s *AnimatedSprite) Play(name string) error {
+	a, ok := s.animations[name]
+	if !ok {
+		return fmt.Errorf("Animation %v not found", name)
+	}
+	if len(a.Frames) == 0 {
+		return fmt.Errorf("Animation %v has no frame", name)
+	}
+
+	s.current = a
+	s.index = 0
+	s.backwards = false
+	s.loops = 0
+	s.elapsed = 0
+	s.playing = true
+	s.showFrame()
+	return nil
+}
+
+// Stop stops the animation and shows its first frame
+func (s *AnimatedSprite) Stop() {
+	s.playing = false
+	if s.current != nil {
+		s.index = 0
+		s.elapsed = 0
+		s.showFrame()
+	}
+}
+
+// Pause freezes the animation on the current frame
+func (s *AnimatedSprite) Pause() {
+	s.playing = false
+}
+
+// Resume continues a paused animation
+func (s *AnimatedSprite) Resume() {
+	if s.current != nil {
+		s.playing = true
+	}
+}
+
+// IsPlaying reports whether an animation is running
+func (s *AnimatedSprite) IsPlaying() bool {
+	return s.playing
+}
+
+// CurrentAnimation returns the name of the animation last played
+func (s *AnimatedSprite) CurrentAnimation() string {
+	if s.current == nil {
+		return ""
+	}
+	return s.current.Name
+}
+
+// FrameIndex returns the index of the frame shown in the current animation
+func (s *AnimatedSprite) FrameIndex() int {
+	return s.index
+}
+
+// Speed returns the playback speed multiplier
+func (s *AnimatedSprite) Speed() float64 {
+	return s.speed
+}
+
+// SetSpeed changes the playback speed multiplier, 1 is the normal speed
+func (s *AnimatedSprite) SetSpeed(speed float64) {
+	if speed < 0 {
+		speed = 0
+	}
+	s.speed = speed
+}
+
+// OnFrame registers a function called every time the shown frame changes
+func (s *AnimatedSprite) OnFrame(fn func(index int)) {
+	s.onFrame = fn
+}
+
+// OnComplete registers a function called when an animation ends
+func (s *AnimatedSprite) OnComplete(fn func(name string)) {
+	s.onComplete = fn
+}
+
+// OnUpdate advances the current animation
+func (s *AnimatedSprite) OnUpdate(dt time.Duration) {
+	if !s.playing || s.current == nil {
+		return
+	}
+
+	s.elapsed += time.Duration(float64(dt) * s.speed)
+	for s.playing {
+		duration := s.current.Frames[s.index].Duration
+		if duration <= 0 {
+			duration = DefaultFrameDuration
+		}
+		if s.elapsed < duration {
+			break
+		}
+		s.elapsed -= duration
+		s.advance()
+	}
+}
+
+// advance moves to the next frame according to the play mode
+func (s *AnimatedSprite) advance() {
+	a := s.current
+	last := len(a.Frames) - 1
+
+	switch {
+	case a.Mode == PlayPingPong && last > 0:
+		if s.backwards && s.index == 0 {
+			s.loops++
+			if a.Loops > 0 && s.loops >= a.Loops {
+				s.complete()
+				return
+			}
+			s.backwards = false
+		} else if !s.backwards && s.index == last {
+			s.backwards = true
+		}
+		if s.backwards {
+			s.index--
+		} else {
+			s.index++
+		}
+
+	case s.index < last:
+		s.index++
+
+	case a.Mode == PlayOnce:
+		s.complete()
+		return
+
+	default:
+		s.loops++
+		if a.Loops > 0 && s.loops >= a.Loops {
+			s.complete()
+			return
+		}
+		s.index = 0
+	}
+
+	s.showFrame()
+	if s.onFrame != nil {
+		s.onFrame(s.index)
+	}
+}
+
+func (s *AnimatedSprite) complete() {
+	s.playing = false
+	s.elapsed = 0
+	if s.onComplete != nil {
+		s.onComplete(s.current.Name)
+	}
+}
+
+func (s *AnimatedSprite) showFrame() {
+	s.SetFrame(s.current.Frames[s.index].Frame)
+}
@@ -0,0 +1,251 @@
+package node
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"kiwanoengine.com/kiwano/geom"
+	"kiwanoengine.com/kiwano/render"
+)
+
+// Camera is a 2D camera looking at its position, the center of the view.
+// A scene renders through its camera, which is updated after the scene's
+// node tree and doesn't need to be added to it.
+type Camera struct {
+	NodeProperties
+
+	zoom float32
+
+	target    Node
+	smoothing float32
+	deadzone  geom.Size
+
+	bounds    geom.Rect
+	hasBounds bool
+
+	shakeIntensity float32
+	shakeDuration  time.Duration
+	shakeLeft      time.Duration
+	shakeOffset    geom.Vec2
+
+	postProcess *render.PostProcess
+
+	rand *rand.Rand
+}
+
+// shakeRand is the random source of the cameras without their own
+var shakeRand = rand.New(rand.NewSource(1))
+
+// SeedRandom resets the random source shared by the nodes, such as camera
+// shakes, so that a replayed game draws the same numbers
+func SeedRandom(seed int64) {
+	shakeRand.Seed(seed)
+}
+
+// NewCamera creates a camera with a zoom of 1
+func NewCamera() *Camera {
+	c := &Camera{zoom: 1}
+	c.self = c
+	return c
+}
+
+// Zoom returns the magnification of the camera, 1 by default
+func (c *Camera) Zoom() float32 {
+	return c.zoom
+}
+
+// SetZoom changes the magnification of the camera, values greater than 1
+// zoom in
+func (c *Camera) SetZoom(zoom float32) {
+	if zoom > 0 {
+		c.zoom = zoom
+	}
+}
+
+// Follow makes the camera move to a target node every frame, nil stops
+// following
+func (c *Camera) Follow(target Node) {
+	c.target = target
+}
+
+// Target returns the node followed by the camera
+func (c *Camera) Target() Node {
+	return c.target
+}
+
+// SetSmoothing makes the camera catch up with its target gradually. speed
+// is the rate of the exponential approach per second, 0 snaps to the
+// target at once.
+func (c *Camera) SetSmoothing(speed float32) {
+	c.smoothing = speed
+}
+
+// SetDeadzone sets the size of an area around the center of the view where
+// the target moves without moving the camera
+func (c *Camera) SetDeadzone(width, height float32) {
+	c.deadzone = geom.Size{Width: width, Height: height}
+}
+
+// SetBounds keeps the view inside a rectangle of the world
+func (c *Camera) SetBounds(bounds geom.Rect) {
+	c.bounds = bounds
+	c.hasBounds = true
+}
+
+// ClearBounds lets the camera move anywhere
+func (c *Camera) ClearBounds() {
+	c.hasBounds = false
+}
+
+// SetRand gives the camera its own random source for shakes, nil selects
+// the source seeded by SeedRandom
+func (c *Camera) SetRand(r *rand.Rand) {
+	c.rand = r
+}
+
+// Shake shakes the view by up to intensity world units, decreasing over a
+// duration
+func (c *Camera) Shake(intensity float32, duration time.Duration) {
+	c.shakeIntensity = intensity
+	c.shakeDuration = duration
+	c.shakeLeft = duration
+}
+
+// PostProcess returns the post-processing chain of the camera, or nil
+func (c *Camera) PostProcess() *render.PostProcess {
+	return c.postProcess
+}
+
+// SetPostProcess runs what the camera sees through a post-processing
+// chain, nil removes it
+func (c *Camera) SetPostProcess(p *render.PostProcess) {
+	c.postProcess = p
+}
+
+// OnUpdate follows the target, clamps the camera and updates the shake
+func (c *Camera) OnUpdate(dt time.Duration) {
+	pos := c.Position()
+
+	if c.target != nil {
+		goal := c.followGoal(pos)
+		if c.smoothing > 0 {
+			k := 1 - float32(math.Exp(-float64(c.smoothing)*dt.Seconds()))
+			pos = pos.Add(goal.Sub(pos).Mul(k))
+		} else {
+			pos = goal
+		}
+	}
+	pos = c.clamp(pos)
+	c.SetPosition(pos.X, pos.Y)
+
+	c.shakeOffset = geom.Vec2{}
+	if c.shakeLeft > 0 {
+		c.shakeLeft -= dt
+		if c.shakeLeft > 0 {
+			amount := c.shakeIntensity * float32(c.shakeLeft) / float32(c.shakeDuration)
+			r := c.rand
+			if r == nil {
+				r = shakeRand
+			}
+			c.shakeOffset = geom.Vec2{
+				X: (r.Float32()*2 - 1) * amount,
+				Y: (r.Float32()*2 - 1) * amount,
+			}
+		}
+	}
+}
+
+// followGoal returns the position which brings the target back inside the
+// deadzone
+func (c *Camera) followGoal(pos geom.Vec2) geom.Vec2 {
+	t := c.target.properties()
+	target := t.position
+	if t.parent != nil {
+		target = t.parent.ToWorld(target)
+	}
+
+	halfW, halfH := c.deadzone.Width/2, c.deadzone.Height/2
+	goal := pos
+	if target.X < pos.X-halfW {
+		goal.X = target.X + halfW
+	} else if target.X > pos.X+halfW {
+		goal.X = target.X - halfW
+	}
+	if target.Y < pos.Y-halfH {
+		goal.Y = target.Y + halfH
+	} else if target.Y > pos.Y+halfH {
+		goal.Y = target.Y - halfH
+	}
+	return goal
+}
+
+// clamp keeps the view inside the bounds, a view larger than the bounds is
+// centered on them
+func (c *Camera) clamp(pos geom.Vec2) geom.Vec2 {
+	if !c.hasBounds {
+		return pos
+	}
+
+	_, view := viewArea()
+	halfW, halfH := view.Width/c.zoom/2, view.Height/c.zoom/2
+	clampAxis := func(v, min, length, half float32) float32 {
+		if length < half*2 {
+			return min + length/2
+		}
+		return float32(math.Max(float64(min+half), math.Min(float64(v), float64(min+length-half))))
+	}
+	pos.X = clampAxis(pos.X, c.bounds.X, c.bounds.Width, halfW)
+	pos.Y = clampAxis(pos.Y, c.bounds.Y, c.bounds.Height, halfH)
+	return pos
+}
+
+// View returns the matrix mapping world coordinates to view coordinates,
+// the coordinates of render.Projection without a camera
+func (c *Camera) View() geom.Matrix3 {
+	center, _ := viewArea()
+	pos := c.Position().Add(c.shakeOffset)
+	return geom.Translation(center.X, center.Y).
+		Mul(geom.Scaling(c.zoom, c.zoom)).
+		Mul(geom.Rotation(-c.Rotation())).
+		Mul(geom.Translation(-pos.X, -pos.Y))
+}
+
+// ViewProjection returns the projection to render the world through the
+// camera
+func (c *Camera) ViewProjection() geom.Matrix3 {
+	return render.Projection().Mul(c.View())
+}
+
+// WorldToView converts a point from world coordinates to view coordinates
+func (c *Camera) WorldToView(p geom.Vec2) geom.Vec2 {
+	return c.View().TransformVec2(p)
+}
+
+// ViewToWorld converts a point from view coordinates, like the position
+// returned by render.Unproject, to world coordinates
+func (c *Camera) ViewToWorld(p geom.Vec2) geom.Vec2 {
+	inv, ok := c.View().Invert()
+	if !ok {
+		return geom.Vec2{}
+	}
+	return inv.TransformVec2(p)
+}
+
+// VisibleRect returns the area of the world seen by the camera, ignoring
+// its rotation
+func (c *Camera) VisibleRect() geom.Rect {
+	_, view := viewArea()
+	w, h := view.Width/c.zoom, view.Height/c.zoom
+	pos := c.Position()
+	return geom.Rect{X: pos.X - w/2, Y: pos.Y - h/2, Width: w, Height: h}
+}
+
+// viewArea returns the center and the size of the visible area of the
+// design resolution. It does not read render.Projection, which transitions
+// offset to move whole scenes.
+func viewArea() (geom.Vec2, geom.Size) {
+	r := render.VisibleRect()
+	center := geom.Vec2{X: r.X + r.Width/2, Y: r.Y + r.Height/2}
+	return center, geom.Size{Width: r.Width, Height: r.Height}
+}
@@ -1,14 +1,237 @@
 package node
 
+import (
+	"sort"
+	"time"
+
+	"kiwanoengine.com/kiwano/render"
+)
+
+// Node is an object in the scene graph
 type Node interface {
+	// OnUpdate is called once per frame before the node's children are updated
+	OnUpdate(time.Duration)
+	// OnRender draws the node itself, children are drawn by Render
 	OnRender()
+
+	properties() *NodeProperties
 }
 
+// NodeProperties holds the state shared by all nodes, including their place
+// in the scene graph. Embed it into a struct to create a custom node.
 type NodeProperties struct {
-	Position struct {
-		X, Y float32
+	transform
+
+	self         Node
+	parent       *NodeProperties
+	children     []Node
+	zOrder       int
+	needsSorting bool
+	actions      []*runningAction
+
+	// opacity is stored as transparency so that the zero value is opaque
+	transparency float32
+}
+
+// New creates an empty node, which is useful as a container of other nodes
+func New() *NodeProperties {
+	n := &NodeProperties{}
+	n.self = n
+	return n
+}
+
+func (n *NodeProperties) properties() *NodeProperties {
+	return n
+}
+
+// OnUpdate does nothing by default
+func (n *NodeProperties) OnUpdate(dt time.Duration) {
+}
+
+// OnRender does nothing by default
+func (n *NodeProperties) OnRender() {
+}
+
+// Parent returns the parent node, or nil if the node is not in a tree
+func (n *NodeProperties) Parent() Node {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent.self
+}
+
+// Children returns a copy of the child list in drawing order
+func (n *NodeProperties) Children() []Node {
+	n.sortChildren()
+	return append([]Node(nil), n.children...)
+}
+
+// AddChild appends a child node, removing it from its previous parent first
+func (n *NodeProperties) AddChild(child Node) {
+	p := bind(child)
+	if p == n {
+		panic("node: a node cannot be added to itself")
 	}
-	Anchor struct {
-		X, Y float32
+	if p.parent != nil {
+		p.parent.detach(p)
 	}
+
+	p.parent = n
+	p.worldValid = false
+	n.children = append(n.children, child)
+	n.needsSorting = true
+}
+
+// RemoveChild detaches a child node and stops the actions and timers of
+// its subtree, it does nothing if child is not a child of this node
+func (n *NodeProperties) RemoveChild(child Node) {
+	p := child.properties()
+	if p.parent != n {
+		return
+	}
+	n.detach(p)
+	StopActions(child)
+}
+
+func (n *NodeProperties) detach(p *NodeProperties) {
+	for i, c := range n.children {
+		if c.properties() == p {
+			// Build a new slice so that a traversal iterating over the old
+			// one is not affected
+			n.children = append(n.children[:i:i], n.children[i+1:]...)
+			break
+		}
+	}
+	p.parent = nil
+	p.worldValid = false
+}
+
+// RemoveAllChildren detaches all the child nodes and stops the actions and
+// timers of their subtrees
+func (n *NodeProperties) RemoveAllChildren() {
+	children := n.children
+	n.children = nil
+	for _, c := range children {
+		p := c.properties()
+		p.parent = nil
+		p.worldValid = false
+		StopActions(c)
+	}
+}
+
+// RemoveFromParent detaches the node from its parent like RemoveChild
+func (n *NodeProperties) RemoveFromParent() {
+	if n.parent != nil {
+		n.parent.RemoveChild(n.self)
+	}
+}
+
+// ZOrder returns the drawing order of the node among its siblings
+func (n *NodeProperties) ZOrder() int {
+	return n.zOrder
+}
+
+// SetZOrder changes the drawing order of the node among its siblings.
+// Nodes with a higher z-order are drawn on top, children with a negative
+// z-order are drawn behind their parent.
+func (n *NodeProperties) SetZOrder(z int) {
+	if n.zOrder == z {
+		return
+	}
+	n.zOrder = z
+	if n.parent != nil {
+		n.parent.needsSorting = true
+	}
+}
+
+// Opacity returns the opacity of the node, 1 by default
+func (n *NodeProperties) Opacity() float32 {
+	return 1 - n.transparency
+}
+
+// SetOpacity changes the opacity of the node and its children, in [0, 1]
+func (n *NodeProperties) SetOpacity(opacity float32) {
+	n.transparency = 1 - opacity
+}
+
+// DisplayedOpacity returns the opacity of the node multiplied by the
+// opacity of all its ancestors
+func (n *NodeProperties) DisplayedOpacity() float32 {
+	opacity := n.Opacity()
+	for p := n.parent; p != nil; p = p.parent {
+		opacity *= p.Opacity()
+	}
+	return opacity
+}
+
+func (n *NodeProperties) sortChildren() {
+	if !n.needsSorting {
+		return
+	}
+	// Copy before sorting, a traversal may be iterating over the old slice
+	children := append([]Node(nil), n.children...)
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].properties().zOrder < children[j].properties().zOrder
+	})
+	n.children = children
+	n.needsSorting = false
+}
+
+// StopActions stops the actions and timers of a node and all its
+// descendants
+func StopActions(n Node) {
+	p := n.properties()
+	p.StopAllActions()
+	for _, child := range p.children {
+		StopActions(child)
+	}
+}
+
+// Update runs the actions of a node and updates it, then all its
+// descendants
+func Update(n Node, dt time.Duration) {
+	p := bind(n)
+	p.runActions(dt)
+	n.OnUpdate(dt)
+
+	for _, child := range p.children {
+		// Skip children removed by a previous sibling during this update
+		if child.properties().parent == p {
+			Update(child, dt)
+		}
+	}
+}
+
+// Render draws a node and all its descendants ordered by z-order
+func Render(n Node) {
+	p := bind(n)
+	p.sortChildren()
+
+	children := p.children
+	i := 0
+	for ; i < len(children) && children[i].properties().zOrder < 0; i++ {
+		Render(children[i])
+	}
+
+	n.OnRender()
+
+	for ; i < len(children); i++ {
+		Render(children[i])
+	}
+}
+
+// RenderTo draws a node and all its descendants into a render target, in
+// the coordinates of the target
+func RenderTo(target *render.RenderTarget, n Node) {
+	target.Begin()
+	Render(n)
+	target.End()
+}
+
+// bind records the outer node which embeds the properties, so that Parent
+// can return it
+func bind(n Node) *NodeProperties {
+	p := n.properties()
+	p.self = n
+	return p
 }
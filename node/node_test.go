@@ -0,0 +1,109 @@
+package node
+
+import (
+	"math"
+	"testing"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+func nearVec2(a, b geom.Vec2) bool {
+	return math.Abs(float64(a.X-b.X)) < 1e-4 && math.Abs(float64(a.Y-b.Y)) < 1e-4
+}
+
+func TestLocalTransform(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(n *NodeProperties)
+		in    geom.Vec2
+		want  geom.Vec2
+	}{
+		{"default", func(n *NodeProperties) {}, geom.Vec2{X: 3, Y: 4}, geom.Vec2{X: 3, Y: 4}},
+		{"position", func(n *NodeProperties) { n.SetPosition(10, 20) }, geom.Vec2{}, geom.Vec2{X: 10, Y: 20}},
+		{"scale", func(n *NodeProperties) { n.SetScale(2, 3) }, geom.Vec2{X: 1, Y: 1}, geom.Vec2{X: 2, Y: 3}},
+		{"rotation", func(n *NodeProperties) { n.SetRotation(90) }, geom.Vec2{X: 1, Y: 0}, geom.Vec2{X: 0, Y: 1}},
+		{"anchor at center", func(n *NodeProperties) {
+			n.SetSize(100, 50)
+			n.SetAnchor(0.5, 0.5)
+			n.SetPosition(10, 10)
+		}, geom.Vec2{X: 50, Y: 25}, geom.Vec2{X: 10, Y: 10}},
+		{"rotation around anchor", func(n *NodeProperties) {
+			n.SetSize(10, 10)
+			n.SetAnchor(0.5, 0.5)
+			n.SetRotation(180)
+		}, geom.Vec2{X: 0, Y: 0}, geom.Vec2{X: 5, Y: 5}},
+	}
+	for _, tt := range tests {
+		n := New()
+		tt.setup(n)
+		if got := n.ToWorld(tt.in); !nearVec2(got, tt.want) {
+			t.Errorf("%v: %v transformed to %v, want %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWorldTransformPropagation(t *testing.T) {
+	root := New()
+	parent := New()
+	child := New()
+	root.AddChild(parent)
+	parent.AddChild(child)
+
+	parent.SetPosition(10, 0)
+	child.SetPosition(1, 2)
+
+	steps := []struct {
+		name   string
+		change func()
+		want   geom.Vec2
+	}{
+		{"initial", func() {}, geom.Vec2{X: 11, Y: 2}},
+		{"parent moves", func() { parent.SetPosition(20, 0) }, geom.Vec2{X: 21, Y: 2}},
+		{"root scales", func() { root.SetScale(2, 2) }, geom.Vec2{X: 42, Y: 4}},
+		{"child moves", func() { child.SetPosition(0, 0) }, geom.Vec2{X: 40, Y: 0}},
+		{"root rotates", func() { root.SetRotation(90) }, geom.Vec2{X: 0, Y: 40}},
+		{"reparented to root", func() { root.AddChild(child) }, geom.Vec2{X: 0, Y: 0}},
+		{"removed", func() { root.RemoveChild(child) }, geom.Vec2{X: 0, Y: 0}},
+	}
+	for _, step := range steps {
+		step.change()
+		if got := child.ToWorld(geom.Vec2{}); !nearVec2(got, step.want) {
+			t.Errorf("%v: child origin at %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestWorldTransformCached(t *testing.T) {
+	parent := New()
+	child := New()
+	parent.AddChild(child)
+	parent.SetPosition(5, 5)
+
+	first := child.WorldTransform()
+	version := child.worldVersion
+	if child.WorldTransform() != first || child.worldVersion != version {
+		t.Error("world transform recomputed without changes")
+	}
+
+	parent.SetPosition(6, 5)
+	child.WorldTransform()
+	if child.worldVersion == version {
+		t.Error("world transform not recomputed after the parent moved")
+	}
+}
+
+func TestToLocal(t *testing.T) {
+	parent := New()
+	child := New()
+	parent.AddChild(child)
+	parent.SetPosition(100, 50)
+	parent.SetRotation(30)
+	child.SetScale(2, 0.5)
+	child.SetSkew(10, 0)
+
+	for _, p := range []geom.Vec2{{}, {X: 1, Y: 2}, {X: -30, Y: 7}} {
+		if got := child.ToLocal(child.ToWorld(p)); !nearVec2(got, p) {
+			t.Errorf("ToLocal(ToWorld(%v)) is %v", p, got)
+		}
+	}
+}
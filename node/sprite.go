@@ -1,15 +1,135 @@
 package node
 
+import (
+	"fmt"
+	"log"
+
+	"kiwanoengine.com/kiwano/render"
+)
+
 type Sprite struct {
 	NodeProperties
 	image string
+	frame *render.Frame
+	color render.Color
 }
 
+// NewSprite creates a sprite from an image file, errors are logged and
+// result in an empty sprite. Use LoadSprite to handle them.
 func NewSprite(image string) *Sprite {
-	return &Sprite{
+	s, err := LoadSprite(image)
+	if err != nil {
+		log.Println("Failed to load sprite:", err)
+	}
+	return s
+}
+
+// LoadSprite creates a sprite from a PNG or JPEG file. Sprites loading the
+// same file share one texture.
+func LoadSprite(image string) (*Sprite, error) {
+	s := &Sprite{
 		image: image,
+		color: render.White,
+	}
+
+	texture, err := render.LoadTexture(image)
+	if err != nil {
+		return s, err
+	}
+
+	s.setFrame(render.NewFrame(texture))
+	return s, nil
+}
+
+// NewSpriteFromTexture creates a sprite drawing a texture, the sprite holds
+// a reference to it
+func NewSpriteFromTexture(texture *render.Texture) *Sprite {
+	return NewSpriteFromFrame(render.NewFrame(texture))
+}
+
+// NewSpriteFromFrame creates a sprite drawing a frame of an atlas
+func NewSpriteFromFrame(frame *render.Frame) *Sprite {
+	s := &Sprite{
+		color: render.White,
+	}
+	s.SetFrame(frame)
+	return s
+}
+
+// NewSpriteFromAtlas creates a sprite drawing the frame of an atlas with
+// the given name
+func NewSpriteFromAtlas(atlas *render.Atlas, name string) (*Sprite, error) {
+	frame := atlas.Frame(name)
+	if frame == nil {
+		return nil, fmt.Errorf("Frame %v not found in atlas", name)
+	}
+	return NewSpriteFromFrame(frame), nil
+}
+
+// Frame returns the frame drawn by the sprite
+func (s *Sprite) Frame() *render.Frame {
+	return s.frame
+}
+
+// SetFrame changes the frame drawn by the sprite and resizes the sprite to
+// the original size of the frame. The sprite holds a reference to the
+// texture of the frame.
+func (s *Sprite) SetFrame(frame *render.Frame) {
+	if frame != nil {
+		frame.Texture.Retain()
+	}
+	s.setFrame(frame)
+}
+
+// setFrame takes ownership of a reference to the frame's texture
+func (s *Sprite) setFrame(frame *render.Frame) {
+	if s.frame != nil {
+		s.frame.Texture.Release()
+	}
+	s.frame = frame
+	if frame != nil {
+		s.SetSize(frame.SourceSize.Width, frame.SourceSize.Height)
+	}
+}
+
+// Image returns the path of the image file
+func (s *Sprite) Image() string {
+	return s.image
+}
+
+// Texture returns the texture of the sprite
+func (s *Sprite) Texture() *render.Texture {
+	if s.frame == nil {
+		return nil
 	}
+	return s.frame.Texture
+}
+
+// Color returns the tint of the sprite
+func (s *Sprite) Color() render.Color {
+	return s.color
+}
+
+// SetColor changes the tint multiplied with the pixels of the sprite
+func (s *Sprite) SetColor(color render.Color) {
+	s.color = color
+}
+
+// Destroy releases the texture of the sprite
+func (s *Sprite) Destroy() {
+	s.setFrame(nil)
 }
 
 func (s *Sprite) OnRender() {
+	if s.frame == nil {
+		return
+	}
+
+	color := s.color
+	color.Alpha *= s.DisplayedOpacity()
+	if color.Alpha <= 0 {
+		return
+	}
+
+	render.DrawFrame(s.frame, s.WorldTransform(), color)
 }
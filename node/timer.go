@@ -0,0 +1,63 @@
+package node
+
+import "time"
+
+// Timer is a callback scheduled on a node with After or Every. Timers run
+// like actions, so they pause with the scene, follow its time scale and
+// are cancelled when the node is removed.
+type Timer struct {
+	interval time.Duration
+	elapsed  time.Duration
+	repeat   bool
+	fn       func()
+	stopped  bool
+}
+
+// After calls fn once after a delay
+func (n *NodeProperties) After(delay time.Duration, fn func()) *Timer {
+	t := &Timer{interval: delay, fn: fn}
+	n.RunAction(t)
+	return t
+}
+
+// Every calls fn repeatedly at an interval until the timer is stopped
+func (n *NodeProperties) Every(interval time.Duration, fn func()) *Timer {
+	t := &Timer{interval: interval, repeat: true, fn: fn}
+	n.RunAction(t)
+	return t
+}
+
+// Stop cancels the timer
+func (t *Timer) Stop() {
+	t.stopped = true
+}
+
+// Stopped reports whether the timer was stopped, directly or with its node,
+// or has fired once for a one-shot timer
+func (t *Timer) Stopped() bool {
+	return t.stopped
+}
+
+// OnStop implements Stopper, the timer is stopped with its node
+func (t *Timer) OnStop() {
+	t.stopped = true
+}
+
+// Step implements Action
+func (t *Timer) Step(dt time.Duration) bool {
+	t.elapsed += dt
+	for !t.stopped && t.elapsed >= t.interval {
+		t.elapsed -= t.interval
+		if !t.repeat {
+			t.stopped = true
+		}
+		t.fn()
+
+		if t.interval <= 0 {
+			// Call timers without interval once per frame
+			t.elapsed = 0
+			break
+		}
+	}
+	return t.stopped
+}
@@ -0,0 +1,24 @@
+package node
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimerStoppedWithNode(t *testing.T) {
+	parent := New()
+	child := New()
+	parent.AddChild(child)
+
+	fired := false
+	timer := child.After(time.Second, func() { fired = true })
+	parent.RemoveChild(child)
+	if !timer.Stopped() {
+		t.Error("timer not stopped after its node was removed")
+	}
+
+	timer.Step(2 * time.Second)
+	if fired {
+		t.Error("timer fired after its node was removed")
+	}
+}
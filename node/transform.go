@@ -0,0 +1,163 @@
+package node
+
+import "kiwanoengine.com/kiwano/geom"
+
+// transformVersion is increased every time a world transform is computed, so
+// that children can tell whether their parent's world transform has changed
+var transformVersion uint64
+
+// transform holds the geometry of a node and its cached matrices. The zero
+// value is a valid identity transform.
+type transform struct {
+	position geom.Vec2
+	anchor   geom.Vec2
+	scale    geom.Vec2
+	skew     geom.Vec2
+	size     geom.Size
+	rotation float32
+	scaleSet bool
+
+	localTransform geom.Matrix3
+	localValid     bool
+
+	worldTransform geom.Matrix3
+	worldValid     bool
+	worldVersion   uint64
+	parentVersion  uint64
+}
+
+// Position returns the position of the node in its parent's coordinates
+func (n *NodeProperties) Position() geom.Vec2 {
+	return n.position
+}
+
+// SetPosition moves the node
+func (n *NodeProperties) SetPosition(x, y float32) {
+	n.position = geom.Vec2{X: x, Y: y}
+	n.localValid = false
+}
+
+// Anchor returns the anchor point of the node, normalized to its size.
+// (0, 0) is the top-left corner and (1, 1) the bottom-right one.
+func (n *NodeProperties) Anchor() geom.Vec2 {
+	return n.anchor
+}
+
+// SetAnchor changes the point of the node placed at its position, around
+// which it is scaled and rotated
+func (n *NodeProperties) SetAnchor(x, y float32) {
+	n.anchor = geom.Vec2{X: x, Y: y}
+	n.localValid = false
+}
+
+// Scale returns the scale factors of the node, (1, 1) by default
+func (n *NodeProperties) Scale() geom.Vec2 {
+	if !n.scaleSet {
+		return geom.Vec2{X: 1, Y: 1}
+	}
+	return n.scale
+}
+
+// SetScale changes the scale factors of the node
+func (n *NodeProperties) SetScale(x, y float32) {
+	n.scale = geom.Vec2{X: x, Y: y}
+	n.scaleSet = true
+	n.localValid = false
+}
+
+// Rotation returns the rotation of the node in degrees
+func (n *NodeProperties) Rotation() float32 {
+	return n.rotation
+}
+
+// SetRotation changes the clockwise rotation of the node in degrees
+func (n *NodeProperties) SetRotation(angle float32) {
+	n.rotation = angle
+	n.localValid = false
+}
+
+// Skew returns the skew angles of the node in degrees
+func (n *NodeProperties) Skew() geom.Vec2 {
+	return n.skew
+}
+
+// SetSkew changes the skew angles of the node in degrees
+func (n *NodeProperties) SetSkew(x, y float32) {
+	n.skew = geom.Vec2{X: x, Y: y}
+	n.localValid = false
+}
+
+// Size returns the size of the node's content
+func (n *NodeProperties) Size() geom.Size {
+	return n.size
+}
+
+// SetSize changes the size of the node's content
+func (n *NodeProperties) SetSize(width, height float32) {
+	n.size = geom.Size{Width: width, Height: height}
+	n.localValid = false
+}
+
+// LocalTransform returns the transform from the node's coordinates to its
+// parent's coordinates
+func (n *NodeProperties) LocalTransform() geom.Matrix3 {
+	if !n.localValid {
+		scale := n.Scale()
+		anchor := geom.Vec2{X: n.anchor.X * n.size.Width, Y: n.anchor.Y * n.size.Height}
+
+		n.localTransform = geom.Translation(n.position.X, n.position.Y).
+			Mul(geom.Rotation(n.rotation)).
+			Mul(geom.Skewing(n.skew.X, n.skew.Y)).
+			Mul(geom.Scaling(scale.X, scale.Y)).
+			Mul(geom.Translation(-anchor.X, -anchor.Y))
+		n.localValid = true
+		n.worldValid = false
+	}
+	return n.localTransform
+}
+
+// WorldTransform returns the transform from the node's coordinates to the
+// coordinates of the root of its tree
+func (n *NodeProperties) WorldTransform() geom.Matrix3 {
+	n.updateWorldTransform()
+	return n.worldTransform
+}
+
+func (n *NodeProperties) updateWorldTransform() {
+	local := n.LocalTransform()
+
+	var parentVersion uint64
+	if n.parent != nil {
+		n.parent.updateWorldTransform()
+		parentVersion = n.parent.worldVersion
+	}
+
+	if n.worldValid && n.parentVersion == parentVersion {
+		return
+	}
+
+	if n.parent != nil {
+		n.worldTransform = n.parent.worldTransform.Mul(local)
+	} else {
+		n.worldTransform = local
+	}
+
+	transformVersion++
+	n.worldVersion = transformVersion
+	n.parentVersion = parentVersion
+	n.worldValid = true
+}
+
+// ToWorld converts a point from the node's coordinates to world coordinates
+func (n *NodeProperties) ToWorld(p geom.Vec2) geom.Vec2 {
+	return n.WorldTransform().TransformVec2(p)
+}
+
+// ToLocal converts a point from world coordinates to the node's coordinates
+func (n *NodeProperties) ToLocal(p geom.Vec2) geom.Vec2 {
+	inv, ok := n.WorldTransform().Invert()
+	if !ok {
+		return geom.Vec2{}
+	}
+	return inv.TransformVec2(p)
+}
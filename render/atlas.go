@@ -0,0 +1,352 @@
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// Atlas is a set of named frames packed into one or more textures
+type Atlas struct {
+	frames   []*Frame
+	byName   map[string][]*Frame
+	tags     []AtlasTag
+	textures []*Texture
+}
+
+// AtlasTag is a named range of frames, like the tags of Aseprite exports
+type AtlasTag struct {
+	Name string
+	// From and To are inclusive indices in the order of Frames
+	From, To int
+	// Direction is "forward", "reverse", "pingpong" or "pingpong_reverse"
+	Direction string
+	// Repeat is the number of times the range is played, 0 means forever
+	Repeat int
+}
+
+// LoadAtlas loads a TexturePacker JSON file (hash or array format) or a
+// libGDX .atlas file, depending on the extension. Texture paths are
+// relative to the atlas file.
+func LoadAtlas(path string) (*Atlas, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		pages []atlasPage
+		tags  []AtlasTag
+	)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		pages, tags, err = parseTexturePacker(data)
+	} else {
+		pages, err = parseLibGDX(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse atlas %v: %v", path, err)
+	}
+
+	atlas := &Atlas{
+		byName: make(map[string][]*Frame),
+		tags:   tags,
+	}
+	dir := filepath.Dir(path)
+	for _, page := range pages {
+		texture, err := LoadTexture(filepath.Join(dir, page.image))
+		if err != nil {
+			atlas.Release()
+			return nil, err
+		}
+		if page.options != (TextureOptions{}) {
+			texture.SetOptions(page.options)
+		}
+		atlas.textures = append(atlas.textures, texture)
+
+		for _, frame := range page.frames {
+			frame.Texture = texture
+			atlas.add(frame)
+		}
+	}
+
+	for _, frames := range atlas.byName {
+		sort.SliceStable(frames, func(i, j int) bool {
+			return frames[i].Index < frames[j].Index
+		})
+	}
+	return atlas, nil
+}
+
+func (a *Atlas) add(frame *Frame) {
+	a.frames = append(a.frames, frame)
+	a.byName[frame.Name] = append(a.byName[frame.Name], frame)
+}
+
+// Frame returns the frame with the given name, or nil if there is none.
+// For indexed libGDX regions it returns the one with the lowest index.
+func (a *Atlas) Frame(name string) *Frame {
+	if frames := a.byName[name]; len(frames) > 0 {
+		return frames[0]
+	}
+	return nil
+}
+
+// FramesNamed returns all the frames with the given name ordered by index
+func (a *Atlas) FramesNamed(name string) []*Frame {
+	return append([]*Frame(nil), a.byName[name]...)
+}
+
+// Frames returns all the frames in the order of the atlas file
+func (a *Atlas) Frames() []*Frame {
+	return append([]*Frame(nil), a.frames...)
+}
+
+// Tags returns the frame tags of the atlas
+func (a *Atlas) Tags() []AtlasTag {
+	return append([]AtlasTag(nil), a.tags...)
+}
+
+// Release releases the textures of the atlas
+func (a *Atlas) Release() {
+	for _, t := range a.textures {
+		t.Release()
+	}
+	a.textures = nil
+}
+
+type atlasPage struct {
+	image   string
+	options TextureOptions
+	frames  []*Frame
+}
+
+type texturePackerRect struct {
+	X, Y, W, H float32
+}
+
+type texturePackerFrame struct {
+	Filename         string
+	Frame            texturePackerRect
+	Rotated          bool
+	Trimmed          bool
+	SpriteSourceSize texturePackerRect
+	SourceSize       texturePackerRect
+	// Duration in milliseconds, written by Aseprite
+	Duration int
+}
+
+type texturePackerFile struct {
+	Frames json.RawMessage
+	Meta   struct {
+		Image     string
+		FrameTags []struct {
+			Name      string
+			From, To  int
+			Direction string
+			Repeat    string
+		}
+	}
+}
+
+// parseTexturePacker reads the TexturePacker JSON format, and the frame
+// durations and tags added by Aseprite which exports the same format
+func parseTexturePacker(data []byte) ([]atlasPage, []AtlasTag, error) {
+	var file texturePackerFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, err
+	}
+
+	frames, err := parseTexturePackerFrames(file.Frames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var tags []AtlasTag
+	for _, t := range file.Meta.FrameTags {
+		if t.From < 0 || t.To >= len(frames) || t.From > t.To {
+			return nil, nil, fmt.Errorf("tag %v has an invalid frame range", t.Name)
+		}
+		repeat, _ := strconv.Atoi(t.Repeat)
+		tags = append(tags, AtlasTag{
+			Name:      t.Name,
+			From:      t.From,
+			To:        t.To,
+			Direction: t.Direction,
+			Repeat:    repeat,
+		})
+	}
+
+	page := atlasPage{image: file.Meta.Image}
+	for _, f := range frames {
+		frame := &Frame{
+			Name:       f.Filename,
+			Index:      -1,
+			Rect:       geom.Rect{X: f.Frame.X, Y: f.Frame.Y, Width: f.Frame.W, Height: f.Frame.H},
+			Rotated:    f.Rotated,
+			SourceSize: geom.Size{Width: f.Frame.W, Height: f.Frame.H},
+			Duration:   time.Duration(f.Duration) * time.Millisecond,
+		}
+		if f.Trimmed {
+			frame.Offset = geom.Vec2{X: f.SpriteSourceSize.X, Y: f.SpriteSourceSize.Y}
+			frame.SourceSize = geom.Size{Width: f.SourceSize.W, Height: f.SourceSize.H}
+		}
+		page.frames = append(page.frames, frame)
+	}
+	return []atlasPage{page}, tags, nil
+}
+
+// parseTexturePackerFrames decodes the frames of the array format, or of
+// the hash format keeping the order of the keys
+func parseTexturePackerFrames(data json.RawMessage) ([]texturePackerFrame, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, fmt.Errorf("no frames")
+	}
+
+	var frames []texturePackerFrame
+	if data[0] == '[' {
+		err := json.Unmarshal(data, &frames)
+		return frames, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var frame texturePackerFrame
+		if err := dec.Decode(&frame); err != nil {
+			return nil, err
+		}
+		frame.Filename = token.(string)
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// libGDXValueCounts is the number of values expected by region properties
+var libGDXValueCounts = map[string]int{
+	"xy": 2, "size": 2, "orig": 2, "offset": 2,
+	"bounds": 4, "offsets": 4, "index": 1,
+}
+
+// parseLibGDX reads the libGDX atlas format, both the legacy layout with
+// xy/size/orig/offset entries and the newer one with bounds/offsets
+func parseLibGDX(r io.Reader) ([]atlasPage, error) {
+	var (
+		pages []atlasPage
+		page  *atlasPage
+		frame *Frame
+		// libGDX offsets start from the bottom of the original image
+		offsetY float32
+	)
+
+	finishFrame := func() {
+		if frame == nil {
+			return
+		}
+		if frame.SourceSize == (geom.Size{}) {
+			frame.SourceSize = geom.Size{Width: frame.Rect.Width, Height: frame.Rect.Height}
+		}
+		frame.Offset.Y = frame.SourceSize.Height - offsetY - frame.Rect.Height
+		page.frames = append(page.frames, frame)
+		frame, offsetY = nil, 0
+	}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			finishFrame()
+			page = nil
+			continue
+		}
+
+		colon := strings.Index(text, ":")
+		if colon < 0 {
+			if page == nil {
+				pages = append(pages, atlasPage{image: text})
+				page = &pages[len(pages)-1]
+			} else {
+				finishFrame()
+				frame = &Frame{Name: text, Index: -1}
+			}
+			continue
+		}
+		if page == nil {
+			return nil, fmt.Errorf("line %d: property outside of a page", line)
+		}
+
+		key := strings.TrimSpace(text[:colon])
+		values := strings.Split(text[colon+1:], ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+
+		if frame == nil {
+			if key == "filter" && len(values) > 0 && values[0] == "Nearest" {
+				page.options.Filter = FilterNearest
+			}
+			if key == "filter" && len(values) > 0 && strings.Contains(values[0], "MipMap") {
+				page.options.Mipmaps = true
+			}
+			if key == "repeat" && len(values) > 0 && values[0] != "none" {
+				page.options.Wrap = WrapRepeat
+			}
+			continue
+		}
+
+		nums := make([]float32, len(values))
+		for i, v := range values {
+			n, _ := strconv.ParseFloat(v, 32)
+			nums[i] = float32(n)
+		}
+		if n, ok := libGDXValueCounts[key]; ok && len(nums) < n {
+			return nil, fmt.Errorf("line %d: %v expects %d values", line, key, n)
+		}
+
+		switch key {
+		case "rotate":
+			// libGDX rotates regions counter-clockwise
+			frame.Rotated = values[0] == "true" || values[0] == "90"
+			frame.CounterClockwise = frame.Rotated
+		case "xy":
+			frame.Rect.X, frame.Rect.Y = nums[0], nums[1]
+		case "size":
+			frame.Rect.Width, frame.Rect.Height = nums[0], nums[1]
+		case "bounds":
+			frame.Rect = geom.Rect{X: nums[0], Y: nums[1], Width: nums[2], Height: nums[3]}
+		case "orig":
+			frame.SourceSize = geom.Size{Width: nums[0], Height: nums[1]}
+		case "offset":
+			frame.Offset.X, offsetY = nums[0], nums[1]
+		case "offsets":
+			frame.Offset.X, offsetY = nums[0], nums[1]
+			frame.SourceSize = geom.Size{Width: nums[2], Height: nums[3]}
+		case "index":
+			frame.Index = int(nums[0])
+		}
+	}
+	finishFrame()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
@@ -0,0 +1,71 @@
+package render
+
+import (
+	"image"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// Backend is the graphics API used by the renderer. Resources are
+// identified by uint32 names like OpenGL objects, 0 is never a valid name.
+type Backend interface {
+	// Clear fills the whole framebuffer with a color
+	Clear(color Color)
+	// Viewport sets the area of the framebuffer drawn into, the origin is
+	// at the bottom-left corner like in OpenGL
+	Viewport(x, y, width, height int)
+
+	NewTexture(img *image.RGBA, options TextureOptions) uint32
+	SetTextureOptions(texture uint32, options TextureOptions)
+	BindTexture(unit uint32, texture uint32)
+	DeleteTexture(texture uint32)
+
+	// NewShader returns a *ShaderError when a stage fails to compile
+	NewShader(vertexSource, fragmentSource string) (uint32, error)
+	UseShader(shader uint32)
+	DeleteShader(shader uint32)
+	// Uniforms are set on the given shader, which becomes the shader in use
+	SetUniformInt(shader uint32, name string, values ...int32)
+	SetUniformFloat(shader uint32, name string, values ...float32)
+	SetUniformMatrix3(shader uint32, name string, m geom.Matrix3)
+
+	// NewBuffer creates a dynamic vertex buffer with room for capacity
+	// vertices and a static index buffer
+	NewBuffer(capacity int, indices []uint16) uint32
+	UpdateBuffer(buffer uint32, vertices []Vertex)
+	DeleteBuffer(buffer uint32)
+
+	SetBlendMode(mode BlendMode)
+	// DrawBuffer draws count indices of a buffer as triangles with the
+	// current shader, textures and blend mode
+	DrawBuffer(buffer uint32, count int)
+
+	// NewRenderTarget creates a framebuffer drawing into a texture
+	NewRenderTarget(texture uint32) (uint32, error)
+	// BindRenderTarget redirects clears and draws to a render target, 0 is
+	// the framebuffer of the window
+	BindRenderTarget(target uint32)
+	DeleteRenderTarget(target uint32)
+	// ReadPixels reads an area of the bound render target, the origin is at
+	// the bottom-left corner and the first row of the image is the bottom
+	// row of the area like in OpenGL
+	ReadPixels(x, y, width, height int) *image.RGBA
+}
+
+var backend Backend
+
+// SetBackend selects the backend used by the renderer, it must be called
+// before creating any resource
+func SetBackend(b Backend) {
+	backend = b
+}
+
+// CurrentBackend returns the backend used by the renderer
+func CurrentBackend() Backend {
+	return backend
+}
+
+// Clear fills the framebuffer with a color
+func Clear(color Color) {
+	backend.Clear(color)
+}
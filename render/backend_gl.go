@@ -0,0 +1,293 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"log"
+
+	"kiwanoengine.com/kiwano/external/gl"
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// GLBackend renders with OpenGL 3.3, it requires a current context
+type GLBackend struct {
+	buffers map[uint32]glBuffer
+	target  uint32
+	shader  uint32
+}
+
+type glBuffer struct {
+	vbo, ebo uint32
+}
+
+// NewGLBackend loads the OpenGL functions of the current context
+func NewGLBackend() (*GLBackend, error) {
+	if err := gl.Init(); err != nil {
+		return nil, err
+	}
+	log.Println("OpenGL version", gl.GetString(gl.VERSION))
+
+	return &GLBackend{
+		buffers: make(map[uint32]glBuffer),
+	}, nil
+}
+
+// Clear ...
+func (b *GLBackend) Clear(color Color) {
+	gl.ClearColor(color.ToVec4())
+	gl.Clear(gl.COLOR_BUFFER_BIT)
+}
+
+// Viewport ...
+func (b *GLBackend) Viewport(x, y, width, height int) {
+	gl.Viewport(int32(x), int32(y), int32(width), int32(height))
+}
+
+// NewTexture ...
+func (b *GLBackend) NewTexture(img *image.RGBA, options TextureOptions) uint32 {
+	var id uint32
+	gl.GenTextures(1, &id)
+	gl.BindTexture(gl.TEXTURE_2D, id)
+	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, int32(img.Rect.Dx()), int32(img.Rect.Dy()), 0,
+		gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	b.SetTextureOptions(id, options)
+	return id
+}
+
+// SetTextureOptions ...
+func (b *GLBackend) SetTextureOptions(texture uint32, options TextureOptions) {
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+
+	var minFilter, magFilter int32 = gl.LINEAR, gl.LINEAR
+	if options.Filter == FilterNearest {
+		minFilter, magFilter = gl.NEAREST, gl.NEAREST
+	}
+	if options.Mipmaps {
+		gl.GenerateMipmap(gl.TEXTURE_2D)
+		if options.Filter == FilterNearest {
+			minFilter = gl.NEAREST_MIPMAP_NEAREST
+		} else {
+			minFilter = gl.LINEAR_MIPMAP_LINEAR
+		}
+	}
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, minFilter)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, magFilter)
+
+	var wrap int32 = gl.CLAMP_TO_EDGE
+	switch options.Wrap {
+	case WrapRepeat:
+		wrap = gl.REPEAT
+	case WrapMirroredRepeat:
+		wrap = gl.MIRRORED_REPEAT
+	}
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_S, wrap)
+	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_WRAP_T, wrap)
+}
+
+// BindTexture ...
+func (b *GLBackend) BindTexture(unit uint32, texture uint32) {
+	gl.ActiveTexture(gl.TEXTURE0 + unit)
+	gl.BindTexture(gl.TEXTURE_2D, texture)
+}
+
+// DeleteTexture ...
+func (b *GLBackend) DeleteTexture(texture uint32) {
+	gl.DeleteTextures(1, &texture)
+}
+
+// NewShader ...
+func (b *GLBackend) NewShader(vertexSource, fragmentSource string) (uint32, error) {
+	var (
+		vertexShader   uint32
+		fragmentShader uint32
+		shaderProgram  uint32
+		err            error
+	)
+
+	if vertexShader, err = gl.CreateAndCompileShader(gl.VERTEX_SHADER, vertexSource); err != nil {
+		return 0, shaderError(VertexStage, err)
+	}
+	defer gl.DeleteShader(vertexShader)
+
+	if fragmentShader, err = gl.CreateAndCompileShader(gl.FRAGMENT_SHADER, fragmentSource); err != nil {
+		return 0, shaderError(FragmentStage, err)
+	}
+	defer gl.DeleteShader(fragmentShader)
+
+	shaderProgram = gl.CreateProgram()
+	if err = gl.LinkShaderProgram(shaderProgram, vertexShader, fragmentShader); err != nil {
+		gl.DeleteProgram(shaderProgram)
+		return 0, err
+	}
+	return shaderProgram, nil
+}
+
+func shaderError(stage ShaderStage, err error) error {
+	if e, ok := err.(*gl.CompileError); ok {
+		return &ShaderError{Stage: stage, Log: e.Log}
+	}
+	return err
+}
+
+// UseShader ...
+func (b *GLBackend) UseShader(shader uint32) {
+	if b.shader != shader {
+		gl.UseProgram(shader)
+		b.shader = shader
+	}
+}
+
+// DeleteShader ...
+func (b *GLBackend) DeleteShader(shader uint32) {
+	gl.DeleteProgram(shader)
+	if b.shader == shader {
+		b.shader = 0
+	}
+}
+
+// SetUniformInt ...
+func (b *GLBackend) SetUniformInt(shader uint32, name string, values ...int32) {
+	b.UseShader(shader)
+	location := gl.GetUniformLocation(shader, name)
+	switch len(values) {
+	case 1:
+		gl.Uniform1i(location, values[0])
+	case 2:
+		gl.Uniform2i(location, values[0], values[1])
+	case 3:
+		gl.Uniform3i(location, values[0], values[1], values[2])
+	case 4:
+		gl.Uniform4i(location, values[0], values[1], values[2], values[3])
+	}
+}
+
+// SetUniformFloat ...
+func (b *GLBackend) SetUniformFloat(shader uint32, name string, values ...float32) {
+	b.UseShader(shader)
+	location := gl.GetUniformLocation(shader, name)
+	switch len(values) {
+	case 1:
+		gl.Uniform1f(location, values[0])
+	case 2:
+		gl.Uniform2f(location, values[0], values[1])
+	case 3:
+		gl.Uniform3f(location, values[0], values[1], values[2])
+	case 4:
+		gl.Uniform4f(location, values[0], values[1], values[2], values[3])
+	}
+}
+
+// SetUniformMatrix3 ...
+func (b *GLBackend) SetUniformMatrix3(shader uint32, name string, m geom.Matrix3) {
+	b.UseShader(shader)
+	gl.UniformMatrix3fv(gl.GetUniformLocation(shader, name), 1, false, &m[0])
+}
+
+// NewBuffer ...
+func (b *GLBackend) NewBuffer(capacity int, indices []uint16) uint32 {
+	var (
+		vao uint32
+		buf glBuffer
+	)
+	gl.GenVertexArrays(1, &vao)
+	gl.GenBuffers(1, &buf.vbo)
+	gl.GenBuffers(1, &buf.ebo)
+
+	gl.BindVertexArray(vao)
+
+	gl.BindBuffer(gl.ARRAY_BUFFER, buf.vbo)
+	gl.BufferData(gl.ARRAY_BUFFER, capacity*vertexSize, nil, gl.DYNAMIC_DRAW)
+
+	gl.BindBuffer(gl.ELEMENT_ARRAY_BUFFER, buf.ebo)
+	gl.BufferData(gl.ELEMENT_ARRAY_BUFFER, len(indices)*2, gl.Ptr(indices), gl.STATIC_DRAW)
+
+	gl.VertexAttribPointer(0, 2, gl.FLOAT, false, vertexSize, gl.PtrOffset(0))
+	gl.EnableVertexAttribArray(0)
+	gl.VertexAttribPointer(1, 2, gl.FLOAT, false, vertexSize, gl.PtrOffset(2*4))
+	gl.EnableVertexAttribArray(1)
+	gl.VertexAttribPointer(2, 4, gl.FLOAT, false, vertexSize, gl.PtrOffset(4*4))
+	gl.EnableVertexAttribArray(2)
+
+	gl.BindVertexArray(0)
+
+	b.buffers[vao] = buf
+	return vao
+}
+
+// UpdateBuffer ...
+func (b *GLBackend) UpdateBuffer(buffer uint32, vertices []Vertex) {
+	if len(vertices) == 0 {
+		return
+	}
+	gl.BindBuffer(gl.ARRAY_BUFFER, b.buffers[buffer].vbo)
+	gl.BufferSubData(gl.ARRAY_BUFFER, 0, len(vertices)*vertexSize, gl.Ptr(vertices))
+}
+
+// DeleteBuffer ...
+func (b *GLBackend) DeleteBuffer(buffer uint32) {
+	buf := b.buffers[buffer]
+	gl.DeleteVertexArrays(1, &buffer)
+	gl.DeleteBuffers(1, &buf.vbo)
+	gl.DeleteBuffers(1, &buf.ebo)
+	delete(b.buffers, buffer)
+}
+
+// SetBlendMode ...
+func (b *GLBackend) SetBlendMode(mode BlendMode) {
+	switch mode {
+	case BlendNone:
+		gl.Disable(gl.BLEND)
+		return
+	case BlendAdditive:
+		gl.BlendFunc(gl.ONE, gl.ONE)
+	case BlendMultiply:
+		gl.BlendFunc(gl.DST_COLOR, gl.ONE_MINUS_SRC_ALPHA)
+	default:
+		gl.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	}
+	gl.Enable(gl.BLEND)
+}
+
+// DrawBuffer ...
+func (b *GLBackend) DrawBuffer(buffer uint32, count int) {
+	gl.BindVertexArray(buffer)
+	gl.DrawElements(gl.TRIANGLES, int32(count), gl.UNSIGNED_SHORT, nil)
+	gl.BindVertexArray(0)
+}
+
+// NewRenderTarget ...
+func (b *GLBackend) NewRenderTarget(texture uint32) (uint32, error) {
+	var id uint32
+	gl.GenFramebuffers(1, &id)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, id)
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.TEXTURE_2D, texture, 0)
+	status := gl.CheckFramebufferStatus(gl.FRAMEBUFFER)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, b.target)
+
+	if status != gl.FRAMEBUFFER_COMPLETE {
+		gl.DeleteFramebuffers(1, &id)
+		return 0, fmt.Errorf("Failed to create framebuffer: status 0x%x", status)
+	}
+	return id, nil
+}
+
+// BindRenderTarget ...
+func (b *GLBackend) BindRenderTarget(target uint32) {
+	b.target = target
+	gl.BindFramebuffer(gl.FRAMEBUFFER, target)
+}
+
+// DeleteRenderTarget ...
+func (b *GLBackend) DeleteRenderTarget(target uint32) {
+	if b.target == target {
+		b.BindRenderTarget(0)
+	}
+	gl.DeleteFramebuffers(1, &target)
+}
+
+// ReadPixels ...
+func (b *GLBackend) ReadPixels(x, y, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	gl.ReadPixels(int32(x), int32(y), int32(width), int32(height), gl.RGBA, gl.UNSIGNED_BYTE, gl.Ptr(img.Pix))
+	return img
+}
@@ -0,0 +1,438 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// SoftwareBackend rasterizes into an image.RGBA without a GPU, which allows
+// running scenes headless and comparing frames with golden images.
+//
+// Shader sources are not executed: every shader behaves like the default
+// shader, using its uProjection uniform and the texture bound to unit 0.
+type SoftwareBackend struct {
+	screen   *image.RGBA
+	target   *image.RGBA
+	viewport image.Rectangle
+	// bottomUp is set while drawing into a texture, whose first row is the
+	// bottom row like in OpenGL
+	bottomUp bool
+
+	nextID   uint32
+	textures map[uint32]*softwareTexture
+	shaders  map[uint32]map[string][]float32
+	buffers  map[uint32]*softwareBuffer
+	targets  map[uint32]uint32
+
+	texture uint32
+	shader  uint32
+	blend   BlendMode
+}
+
+type softwareTexture struct {
+	img     *image.RGBA
+	options TextureOptions
+}
+
+type softwareBuffer struct {
+	vertices []Vertex
+	indices  []uint16
+}
+
+// NewSoftwareBackend creates a backend drawing into a width x height image
+func NewSoftwareBackend(width, height int) *SoftwareBackend {
+	screen := image.NewRGBA(image.Rect(0, 0, width, height))
+	return &SoftwareBackend{
+		screen:   screen,
+		target:   screen,
+		viewport: image.Rect(0, 0, width, height),
+		textures: make(map[uint32]*softwareTexture),
+		shaders:  make(map[uint32]map[string][]float32),
+		buffers:  make(map[uint32]*softwareBuffer),
+		targets:  make(map[uint32]uint32),
+	}
+}
+
+// Image returns the framebuffer, pixels use premultiplied alpha
+func (b *SoftwareBackend) Image() *image.RGBA {
+	return b.screen
+}
+
+func (b *SoftwareBackend) newID() uint32 {
+	b.nextID++
+	return b.nextID
+}
+
+// Clear ...
+func (b *SoftwareBackend) Clear(clearColor Color) {
+	c := clearColor.Premultiplied()
+	fill := image.NewUniform(rgba8(c.R, c.G, c.B, c.Alpha))
+	draw.Draw(b.target, b.target.Rect, fill, image.Point{}, draw.Src)
+}
+
+// Viewport ...
+func (b *SoftwareBackend) Viewport(x, y, width, height int) {
+	if b.bottomUp {
+		b.viewport = image.Rect(x, y, x+width, y+height)
+		return
+	}
+	// Convert from a bottom-left origin to the top-left origin of images
+	top := b.target.Rect.Dy() - y - height
+	b.viewport = image.Rect(x, top, x+width, top+height)
+}
+
+// NewTexture ...
+func (b *SoftwareBackend) NewTexture(img *image.RGBA, options TextureOptions) uint32 {
+	id := b.newID()
+	b.textures[id] = &softwareTexture{toRGBA(img), options}
+	return id
+}
+
+// SetTextureOptions ...
+func (b *SoftwareBackend) SetTextureOptions(texture uint32, options TextureOptions) {
+	if t, ok := b.textures[texture]; ok {
+		t.options = options
+	}
+}
+
+// BindTexture ...
+func (b *SoftwareBackend) BindTexture(unit uint32, texture uint32) {
+	if unit == 0 {
+		b.texture = texture
+	}
+}
+
+// DeleteTexture ...
+func (b *SoftwareBackend) DeleteTexture(texture uint32) {
+	delete(b.textures, texture)
+}
+
+// NewShader ...
+func (b *SoftwareBackend) NewShader(vertexSource, fragmentSource string) (uint32, error) {
+	id := b.newID()
+	b.shaders[id] = make(map[string][]float32)
+	return id, nil
+}
+
+// UseShader ...
+func (b *SoftwareBackend) UseShader(shader uint32) {
+	b.shader = shader
+}
+
+// DeleteShader ...
+func (b *SoftwareBackend) DeleteShader(shader uint32) {
+	delete(b.shaders, shader)
+}
+
+// SetUniformInt ...
+func (b *SoftwareBackend) SetUniformInt(shader uint32, name string, values ...int32) {
+	floats := make([]float32, len(values))
+	for i, v := range values {
+		floats[i] = float32(v)
+	}
+	b.SetUniformFloat(shader, name, floats...)
+}
+
+// SetUniformFloat ...
+func (b *SoftwareBackend) SetUniformFloat(shader uint32, name string, values ...float32) {
+	if uniforms, ok := b.shaders[shader]; ok {
+		uniforms[name] = append([]float32(nil), values...)
+	}
+}
+
+// SetUniformMatrix3 ...
+func (b *SoftwareBackend) SetUniformMatrix3(shader uint32, name string, m geom.Matrix3) {
+	b.SetUniformFloat(shader, name, m[:]...)
+}
+
+// NewBuffer ...
+func (b *SoftwareBackend) NewBuffer(capacity int, indices []uint16) uint32 {
+	id := b.newID()
+	b.buffers[id] = &softwareBuffer{
+		vertices: make([]Vertex, 0, capacity),
+		indices:  append([]uint16(nil), indices...),
+	}
+	return id
+}
+
+// UpdateBuffer ...
+func (b *SoftwareBackend) UpdateBuffer(buffer uint32, vertices []Vertex) {
+	if buf, ok := b.buffers[buffer]; ok {
+		buf.vertices = append(buf.vertices[:0], vertices...)
+	}
+}
+
+// DeleteBuffer ...
+func (b *SoftwareBackend) DeleteBuffer(buffer uint32) {
+	delete(b.buffers, buffer)
+}
+
+// SetBlendMode ...
+func (b *SoftwareBackend) SetBlendMode(mode BlendMode) {
+	b.blend = mode
+}
+
+// DrawBuffer ...
+func (b *SoftwareBackend) DrawBuffer(buffer uint32, count int) {
+	buf, ok := b.buffers[buffer]
+	if !ok {
+		return
+	}
+
+	proj := geom.Identity()
+	if m := b.shaders[b.shader]["uProjection"]; len(m) == len(proj) {
+		copy(proj[:], m)
+	}
+	texture := b.textures[b.texture]
+
+	if count > len(buf.indices) {
+		count = len(buf.indices)
+	}
+	for i := 0; i+2 < count; i += 3 {
+		var tri [3]Vertex
+		for j := range tri {
+			tri[j] = buf.vertices[buf.indices[i+j]]
+			tri[j].X, tri[j].Y = b.toPixel(proj.Transform(tri[j].X, tri[j].Y))
+		}
+		b.drawTriangle(tri, texture)
+	}
+}
+
+// toPixel converts normalized device coordinates to framebuffer pixels
+func (b *SoftwareBackend) toPixel(x, y float32) (float32, float32) {
+	vp := b.viewport
+	if b.bottomUp {
+		y = -y
+	}
+	return float32(vp.Min.X) + (x+1)/2*float32(vp.Dx()),
+		float32(vp.Min.Y) + (1-y)/2*float32(vp.Dy())
+}
+
+// NewRenderTarget ...
+func (b *SoftwareBackend) NewRenderTarget(texture uint32) (uint32, error) {
+	if _, ok := b.textures[texture]; !ok {
+		return 0, fmt.Errorf("Failed to create framebuffer: no texture %v", texture)
+	}
+	id := b.newID()
+	b.targets[id] = texture
+	return id, nil
+}
+
+// BindRenderTarget ...
+func (b *SoftwareBackend) BindRenderTarget(target uint32) {
+	b.target, b.bottomUp = b.screen, false
+	if t, ok := b.textures[b.targets[target]]; ok && target != 0 {
+		b.target, b.bottomUp = t.img, true
+	}
+}
+
+// DeleteRenderTarget ...
+func (b *SoftwareBackend) DeleteRenderTarget(target uint32) {
+	delete(b.targets, target)
+}
+
+// ReadPixels ...
+func (b *SoftwareBackend) ReadPixels(x, y, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for row := 0; row < height; row++ {
+		// Rows of the screen are stored from the top
+		srcY := y + row
+		if !b.bottomUp {
+			srcY = b.target.Rect.Dy() - 1 - srcY
+		}
+		if srcY < 0 || srcY >= b.target.Rect.Dy() {
+			continue
+		}
+		for col := 0; col < width; col++ {
+			if srcX := x + col; srcX >= 0 && srcX < b.target.Rect.Dx() {
+				copy(img.Pix[img.PixOffset(col, row):][:4], b.target.Pix[b.target.PixOffset(srcX, srcY):][:4])
+			}
+		}
+	}
+	return img
+}
+
+func (b *SoftwareBackend) drawTriangle(tri [3]Vertex, texture *softwareTexture) {
+	area := edge(tri[0], tri[1], tri[2].X, tri[2].Y)
+	if area == 0 {
+		return
+	}
+	if area < 0 {
+		tri[1], tri[2] = tri[2], tri[1]
+		area = -area
+	}
+
+	minX := math.Floor(float64(min3(tri[0].X, tri[1].X, tri[2].X)))
+	minY := math.Floor(float64(min3(tri[0].Y, tri[1].Y, tri[2].Y)))
+	maxX := math.Ceil(float64(max3(tri[0].X, tri[1].X, tri[2].X)))
+	maxY := math.Ceil(float64(max3(tri[0].Y, tri[1].Y, tri[2].Y)))
+	bounds := image.Rect(int(minX), int(minY), int(maxX), int(maxY)).
+		Intersect(b.viewport).Intersect(b.target.Rect)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			px, py := float32(x)+0.5, float32(y)+0.5
+
+			w0 := edge(tri[1], tri[2], px, py)
+			w1 := edge(tri[2], tri[0], px, py)
+			w2 := edge(tri[0], tri[1], px, py)
+			if !covers(w0, tri[1], tri[2]) || !covers(w1, tri[2], tri[0]) || !covers(w2, tri[0], tri[1]) {
+				continue
+			}
+			w0, w1, w2 = w0/area, w1/area, w2/area
+
+			r := w0*tri[0].R + w1*tri[1].R + w2*tri[2].R
+			g := w0*tri[0].G + w1*tri[1].G + w2*tri[2].G
+			bl := w0*tri[0].B + w1*tri[1].B + w2*tri[2].B
+			a := w0*tri[0].A + w1*tri[1].A + w2*tri[2].A
+
+			if texture != nil {
+				u := w0*tri[0].U + w1*tri[1].U + w2*tri[2].U
+				v := w0*tri[0].V + w1*tri[1].V + w2*tri[2].V
+				tr, tg, tb, ta := texture.sample(u, v)
+				r, g, bl, a = r*tr, g*tg, bl*tb, a*ta
+			}
+
+			b.blendPixel(x, y, r, g, bl, a)
+		}
+	}
+}
+
+func (b *SoftwareBackend) blendPixel(x, y int, r, g, bl, a float32) {
+	i := b.target.PixOffset(x, y)
+	pix := b.target.Pix[i : i+4 : i+4]
+	dr, dg, db, da := float32(pix[0])/255, float32(pix[1])/255, float32(pix[2])/255, float32(pix[3])/255
+
+	switch b.blend {
+	case BlendNone:
+		dr, dg, db, da = r, g, bl, a
+	case BlendAdditive:
+		dr, dg, db, da = r+dr, g+dg, bl+db, a+da
+	case BlendMultiply:
+		dr, dg, db, da = r*dr+dr*(1-a), g*dg+dg*(1-a), bl*db+db*(1-a), a*da+da*(1-a)
+	default:
+		dr, dg, db, da = r+dr*(1-a), g+dg*(1-a), bl+db*(1-a), a+da*(1-a)
+	}
+
+	c := rgba8(dr, dg, db, da)
+	pix[0], pix[1], pix[2], pix[3] = c.R, c.G, c.B, c.A
+}
+
+// edge returns twice the signed area of the triangle (a, b, p)
+func edge(a, b Vertex, px, py float32) float32 {
+	return (b.X-a.X)*(py-a.Y) - (b.Y-a.Y)*(px-a.X)
+}
+
+// covers applies a top-left fill rule so that pixels on an edge shared by
+// two triangles are drawn only once
+func covers(w float32, a, b Vertex) bool {
+	if w != 0 {
+		return w > 0
+	}
+	dx, dy := b.X-a.X, b.Y-a.Y
+	return dy > 0 || (dy == 0 && dx < 0)
+}
+
+// sample returns the color of the texture at (u, v), mipmaps are ignored
+func (t *softwareTexture) sample(u, v float32) (r, g, b, a float32) {
+	w, h := t.img.Rect.Dx(), t.img.Rect.Dy()
+	x, y := u*float32(w), v*float32(h)
+
+	if t.options.Filter == FilterNearest {
+		return t.texel(int(math.Floor(float64(x))), int(math.Floor(float64(y))))
+	}
+
+	// Bilinear interpolation between the four nearest texel centers
+	x, y = x-0.5, y-0.5
+	x0, y0 := math.Floor(float64(x)), math.Floor(float64(y))
+	fx, fy := x-float32(x0), y-float32(y0)
+	ix, iy := int(x0), int(y0)
+
+	r00, g00, b00, a00 := t.texel(ix, iy)
+	r10, g10, b10, a10 := t.texel(ix+1, iy)
+	r01, g01, b01, a01 := t.texel(ix, iy+1)
+	r11, g11, b11, a11 := t.texel(ix+1, iy+1)
+
+	lerp := func(c00, c10, c01, c11 float32) float32 {
+		top := c00 + (c10-c00)*fx
+		bottom := c01 + (c11-c01)*fx
+		return top + (bottom-top)*fy
+	}
+	return lerp(r00, r10, r01, r11), lerp(g00, g10, g01, g11), lerp(b00, b10, b01, b11), lerp(a00, a10, a01, a11)
+}
+
+// texel returns the texel at (x, y) after applying the wrap mode
+func (t *softwareTexture) texel(x, y int) (r, g, b, a float32) {
+	if t.img.Rect.Empty() {
+		return 0, 0, 0, 0
+	}
+	x = wrapCoord(x, t.img.Rect.Dx(), t.options.Wrap)
+	y = wrapCoord(y, t.img.Rect.Dy(), t.options.Wrap)
+
+	i := t.img.PixOffset(t.img.Rect.Min.X+x, t.img.Rect.Min.Y+y)
+	pix := t.img.Pix[i : i+4 : i+4]
+	return float32(pix[0]) / 255, float32(pix[1]) / 255, float32(pix[2]) / 255, float32(pix[3]) / 255
+}
+
+func wrapCoord(v, size int, wrap TextureWrap) int {
+	if size <= 0 {
+		return 0
+	}
+	switch wrap {
+	case WrapRepeat:
+		v %= size
+		if v < 0 {
+			v += size
+		}
+		return v
+	case WrapMirroredRepeat:
+		period := size * 2
+		v %= period
+		if v < 0 {
+			v += period
+		}
+		if v >= size {
+			v = period - 1 - v
+		}
+		return v
+	default:
+		return clampInt(v, 0, size-1)
+	}
+}
+
+func rgba8(r, g, b, a float32) color.RGBA {
+	return color.RGBA{toByte(r), toByte(g), toByte(b), toByte(a)}
+}
+
+func toByte(v float32) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 1 {
+		return 255
+	}
+	return uint8(v*255 + 0.5)
+}
+
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func min3(a, b, c float32) float32 {
+	return float32(math.Min(float64(a), math.Min(float64(b), float64(c))))
+}
+
+func max3(a, b, c float32) float32 {
+	return float32(math.Max(float64(a), math.Max(float64(b), float64(c))))
+}
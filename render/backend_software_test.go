@@ -0,0 +1,189 @@
+package render
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden images in testdata")
+
+// newTestBackend renders into a software framebuffer of a size in pixels
+func newTestBackend(width, height int) *SoftwareBackend {
+	b := NewSoftwareBackend(width, height)
+	SetBackend(b)
+	Resize(width, height)
+	return b
+}
+
+func destroyTestResources() {
+	DestroyAllBatches()
+	DestroyAllRenderTargets()
+	DestroyAllShaders()
+	DestroyAllTextures()
+}
+
+// checkGolden compares an image with testdata/name.png, -update rewrites it
+func checkGolden(t *testing.T, name string, img *image.RGBA) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".png")
+
+	if *update {
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := png.Encode(f, img); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	golden, err := LoadImage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if golden.Rect.Size() != img.Rect.Size() {
+		t.Fatalf("%v: size %v, want %v", name, img.Rect.Size(), golden.Rect.Size())
+	}
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			got, want := img.RGBAAt(x, y), golden.RGBAAt(x, y)
+			if !closeRGBA(got, want) {
+				t.Fatalf("%v: pixel (%v, %v) is %v, want %v", name, x, y, got, want)
+			}
+		}
+	}
+}
+
+// closeRGBA allows rounding differences between platforms
+func closeRGBA(a, b color.RGBA) bool {
+	near := func(x, y uint8) bool {
+		d := int(x) - int(y)
+		return d >= -1 && d <= 1
+	}
+	return near(a.R, b.R) && near(a.G, b.G) && near(a.B, b.B) && near(a.A, b.A)
+}
+
+// checkerImage returns a 2x2 image with red, green, blue and white texels
+func checkerImage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{0, 255, 0, 255})
+	img.Set(0, 1, color.RGBA{0, 0, 255, 255})
+	img.Set(1, 1, color.RGBA{255, 255, 255, 255})
+	return img
+}
+
+func TestSoftwareBlendModes(t *testing.T) {
+	b := newTestBackend(64, 16)
+	defer destroyTestResources()
+
+	Clear(Color{0.2, 0.4, 0.6, 1})
+	modes := []BlendMode{BlendAlpha, BlendAdditive, BlendMultiply, BlendNone}
+	for i, mode := range modes {
+		DefaultBatch().SetBlendMode(mode)
+		FillRect(geom.Translation(float32(i*16+2), 2), 12, 12, Color{1, 0.5, 0, 0.5})
+	}
+	Flush()
+
+	checkGolden(t, "blend_modes", b.Image())
+}
+
+func TestSoftwareTextureWrap(t *testing.T) {
+	b := newTestBackend(48, 16)
+	defer destroyTestResources()
+
+	Clear(Color{0, 0, 0, 1})
+	wraps := []TextureWrap{WrapClamp, WrapRepeat, WrapMirroredRepeat}
+	for i, wrap := range wraps {
+		texture := NewTextureWithOptions(checkerImage(), TextureOptions{Filter: FilterNearest, Wrap: wrap})
+		vertices := QuadVertices(geom.Translation(float32(i*16), 0), 16, 16, White)
+		// Texture coordinates from -1 to 3 show the texture 4 times
+		for j := range vertices {
+			vertices[j].U = vertices[j].U*4 - 1
+			vertices[j].V = vertices[j].V*4 - 1
+		}
+		DefaultBatch().DrawQuad(texture, vertices)
+	}
+	Flush()
+
+	checkGolden(t, "texture_wrap", b.Image())
+}
+
+func TestSoftwareRenderTarget(t *testing.T) {
+	b := newTestBackend(32, 32)
+	defer destroyTestResources()
+
+	target, err := NewRenderTarget(16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target.Clear(Color{0, 0, 0, 0})
+	target.Begin()
+	// A red bar at the top and a blue square at the bottom-left
+	FillRect(geom.Identity(), 16, 4, Color{1, 0, 0, 1})
+	FillRect(geom.Translation(0, 12), 4, 4, Color{0, 0, 1, 1})
+	target.End()
+
+	pixels := target.ReadPixels()
+	if got := pixels.RGBAAt(0, 0); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("first row of the render target is %v, want red", got)
+	}
+
+	Clear(Color{1, 1, 1, 1})
+	DrawTexture(target.Texture, geom.Translation(8, 8), 16, 16, White)
+	Flush()
+
+	checkGolden(t, "render_target", b.Image())
+}
+
+func TestSoftwareEmptyTexture(t *testing.T) {
+	b := newTestBackend(4, 4)
+	defer destroyTestResources()
+
+	Clear(Color{0, 0, 0, 1})
+	texture := NewTextureWithOptions(image.NewRGBA(image.Rect(0, 0, 0, 0)), TextureOptions{Wrap: WrapRepeat})
+	DrawTexture(texture, geom.Identity(), 4, 4, White)
+	Flush()
+
+	if got := b.Image().RGBAAt(1, 1); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("empty texture drew %v, want nothing", got)
+	}
+}
+
+func TestSoftwareRenderTargetResize(t *testing.T) {
+	b := newTestBackend(8, 8)
+	defer destroyTestResources()
+
+	target, err := NewWindowRenderTarget()
+	if err != nil {
+		t.Fatal(err)
+	}
+	texture := target.Texture
+	var resized [2]int
+	target.OnResize(func(width, height int) { resized = [2]int{width, height} })
+
+	Resize(12, 6)
+	if target.Texture != texture {
+		t.Fatal("resizing replaced the texture of the render target")
+	}
+	if texture.Width != 12 || texture.Height != 6 || resized != [2]int{12, 6} {
+		t.Fatalf("texture is %vx%v and OnResize got %v, want 12x6", texture.Width, texture.Height, resized)
+	}
+
+	target.Clear(Color{0, 1, 0, 1})
+	Clear(Color{0, 0, 0, 1})
+	DrawTexture(texture, geom.Identity(), 12, 6, White)
+	Flush()
+	if got := b.Image().RGBAAt(5, 3); got != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("resized texture drew %v, want green", got)
+	}
+}
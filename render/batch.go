@@ -0,0 +1,192 @@
+package render
+
+import (
+	"log"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// DefaultBatchSize is the number of quads the default batch holds before
+// it has to flush
+const DefaultBatchSize = 8192
+
+// maxBatchSize is the largest number of quads addressable with 16-bit indices
+const maxBatchSize = 65536 / 4
+
+// BlendMode controls how drawn pixels are combined with the framebuffer.
+// All modes expect premultiplied alpha.
+type BlendMode int
+
+const (
+	BlendAlpha BlendMode = iota
+	BlendAdditive
+	BlendMultiply
+	BlendNone
+)
+
+var (
+	batches      map[*Batch]struct{}
+	defaultBatch *Batch
+
+	drawCalls, lastDrawCalls int
+)
+
+// Batch accumulates textured quads in a dynamic vertex buffer and draws
+// them with as few draw calls as possible. It flushes when the texture,
+// shader or blend mode changes, or when it is full.
+type Batch struct {
+	buffer   uint32
+	vertices []Vertex
+
+	texture *Texture
+	shader  *Shader
+	blend   BlendMode
+}
+
+// NewBatch creates a batch holding up to size quads
+func NewBatch(size int) *Batch {
+	if size <= 0 || size > maxBatchSize {
+		size = maxBatchSize
+	}
+
+	b := &Batch{
+		vertices: make([]Vertex, 0, size*4),
+	}
+
+	indices := make([]uint16, size*6)
+	for i := 0; i < size; i++ {
+		v := uint16(i * 4)
+		copy(indices[i*6:], []uint16{v, v + 1, v + 2, v + 2, v + 3, v})
+	}
+
+	b.buffer = backend.NewBuffer(size*4, indices)
+
+	saveBatch(b)
+	return b
+}
+
+// DefaultBatch returns the batch used by DrawTexture, it is created on
+// first use
+func DefaultBatch() *Batch {
+	if defaultBatch == nil {
+		defaultBatch = NewBatch(DefaultBatchSize)
+	}
+	return defaultBatch
+}
+
+// DestroyAllBatches ...
+func DestroyAllBatches() {
+	for b := range batches {
+		b.Destroy()
+	}
+	batches = nil
+	defaultBatch = nil
+}
+
+func saveBatch(b *Batch) {
+	if batches == nil {
+		batches = make(map[*Batch]struct{})
+	}
+	batches[b] = struct{}{}
+}
+
+// SetShader changes the shader used for the next quads, nil selects the
+// default shader
+func (b *Batch) SetShader(shader *Shader) {
+	if b.shader != shader {
+		b.Flush()
+		b.shader = shader
+	}
+}
+
+// SetBlendMode changes the blend mode used for the next quads
+func (b *Batch) SetBlendMode(mode BlendMode) {
+	if b.blend != mode {
+		b.Flush()
+		b.blend = mode
+	}
+}
+
+// DrawQuad queues a quad, vertices are in world coordinates and in the
+// order top-left, top-right, bottom-right, bottom-left
+func (b *Batch) DrawQuad(texture *Texture, vertices [4]Vertex) {
+	if b.texture != texture || len(b.vertices) == cap(b.vertices) {
+		b.Flush()
+		b.texture = texture
+	}
+	b.vertices = append(b.vertices, vertices[:]...)
+}
+
+// Flush draws all the queued quads
+func (b *Batch) Flush() {
+	if len(b.vertices) == 0 {
+		return
+	}
+	defer func() {
+		b.vertices = b.vertices[:0]
+	}()
+
+	shader := b.shader
+	if shader == nil {
+		var err error
+		if shader, err = DefaultShader(); err != nil {
+			log.Println(err)
+			return
+		}
+	}
+
+	backend.SetBlendMode(b.blend)
+
+	shader.Use()
+	shader.SetMatrix3("uProjection", projection)
+	shader.SetInt("uTexture", 0)
+	b.texture.Bind(0)
+
+	backend.UpdateBuffer(b.buffer, b.vertices)
+	backend.DrawBuffer(b.buffer, len(b.vertices)/4*6)
+
+	drawCalls++
+}
+
+// Destroy deletes the buffers of the batch
+func (b *Batch) Destroy() {
+	backend.DeleteBuffer(b.buffer)
+	delete(batches, b)
+}
+
+// DrawTexture queues a texture stretched over the rectangle (0, 0) -
+// (width, height) transformed by m, with its pixels multiplied by color
+func DrawTexture(texture *Texture, m geom.Matrix3, width, height float32, color Color) {
+	DefaultBatch().DrawQuad(texture, QuadVertices(m, width, height, color))
+}
+
+// FillRect queues the rectangle (0, 0) - (width, height) transformed by m,
+// filled with a solid color
+func FillRect(m geom.Matrix3, width, height float32, color Color) {
+	DrawTexture(WhiteTexture(), m, width, height, color)
+}
+
+// Flush draws everything queued in the default batch
+func Flush() {
+	if defaultBatch != nil {
+		defaultBatch.Flush()
+	}
+}
+
+// BeginFrame resets the per-frame statistics and reloads the watched
+// shaders
+func BeginFrame() {
+	drawCalls = 0
+	watchShaders()
+}
+
+// EndFrame flushes pending draws and records the frame statistics
+func EndFrame() {
+	Flush()
+	lastDrawCalls = drawCalls
+}
+
+// DrawCalls returns the number of draw calls issued during the last frame
+func DrawCalls() int {
+	return lastDrawCalls
+}
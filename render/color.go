@@ -0,0 +1,19 @@
+package render
+
+// Color is a RGBA color with components in [0, 1]
+type Color struct {
+	R, G, B float32
+	Alpha   float32
+}
+
+// White is the default tint of drawn textures
+var White = Color{1, 1, 1, 1}
+
+func (c *Color) ToVec4() (float32, float32, float32, float32) {
+	return c.R, c.G, c.B, c.Alpha
+}
+
+// Premultiplied returns the color with its RGB components multiplied by alpha
+func (c Color) Premultiplied() Color {
+	return Color{c.R * c.Alpha, c.G * c.Alpha, c.B * c.Alpha, c.Alpha}
+}
@@ -0,0 +1,51 @@
+package render
+
+const defaultVertexShader = `
+#version 330 core
+layout (location = 0) in vec2 aPos;
+layout (location = 1) in vec2 aTexCoord;
+layout (location = 2) in vec4 aColor;
+
+uniform mat3 uProjection;
+
+out vec2 vTexCoord;
+out vec4 vColor;
+
+void main()
+{
+	vec3 pos = uProjection * vec3(aPos, 1.0);
+	gl_Position = vec4(pos.xy, 0.0, 1.0);
+	vTexCoord = aTexCoord;
+	vColor = aColor;
+}
+`
+
+const defaultFragmentShader = `
+#version 330 core
+in vec2 vTexCoord;
+in vec4 vColor;
+
+uniform sampler2D uTexture;
+
+out vec4 FragColor;
+
+void main()
+{
+	FragColor = texture(uTexture, vTexCoord) * vColor;
+}
+`
+
+var defaultShader *Shader
+
+// DefaultShader returns the shader used to draw textures, it is compiled on
+// first use. Vertex colors and texture pixels use premultiplied alpha.
+func DefaultShader() (*Shader, error) {
+	if defaultShader == nil {
+		shader, err := CreateShader(defaultVertexShader, defaultFragmentShader)
+		if err != nil {
+			return nil, err
+		}
+		defaultShader = shader
+	}
+	return defaultShader, nil
+}
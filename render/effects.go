@@ -0,0 +1,347 @@
+package render
+
+const effectHeader = `
+#version 330 core
+in vec2 vTexCoord;
+in vec4 vColor;
+
+uniform sampler2D uTexture;
+uniform sampler2D uInput;
+uniform vec2 uResolution;
+
+out vec4 FragColor;
+`
+
+const grayscaleFragmentShader = effectHeader + `
+uniform float uAmount;
+
+void main()
+{
+	vec4 color = texture(uTexture, vTexCoord);
+	float gray = dot(color.rgb, vec3(0.299, 0.587, 0.114));
+	FragColor = vec4(mix(color.rgb, vec3(gray), uAmount), color.a);
+}
+`
+
+const blurFragmentShader = effectHeader + `
+uniform vec2 uDirection;
+uniform float uRadius;
+
+const float weights[5] = float[](0.227027, 0.1945946, 0.1216216, 0.054054, 0.016216);
+
+void main()
+{
+	vec2 offset = uDirection / uResolution * uRadius / 4.0;
+	vec4 color = texture(uTexture, vTexCoord) * weights[0];
+	for (int i = 1; i < 5; i++) {
+		color += texture(uTexture, vTexCoord + offset * float(i)) * weights[i];
+		color += texture(uTexture, vTexCoord - offset * float(i)) * weights[i];
+	}
+	FragColor = color;
+}
+`
+
+const brightFragmentShader = effectHeader + `
+uniform float uThreshold;
+
+void main()
+{
+	vec4 color = texture(uTexture, vTexCoord);
+	float brightness = max(color.r, max(color.g, color.b));
+	float weight = max(brightness - uThreshold, 0.0) / max(brightness, 0.0001);
+	FragColor = color * weight;
+}
+`
+
+const bloomFragmentShader = effectHeader + `
+uniform float uIntensity;
+
+void main()
+{
+	vec4 color = texture(uInput, vTexCoord);
+	vec4 glow = texture(uTexture, vTexCoord) * uIntensity;
+	FragColor = vec4(color.rgb + glow.rgb, max(color.a, glow.a));
+}
+`
+
+const crtFragmentShader = effectHeader + `
+uniform float uCurvature;
+uniform float uScanlines;
+
+void main()
+{
+	vec2 uv = vTexCoord * 2.0 - 1.0;
+	uv *= 1.0 + uCurvature * dot(uv.yx, uv.yx);
+	uv = uv * 0.5 + 0.5;
+	if (uv.x < 0.0 || uv.x > 1.0 || uv.y < 0.0 || uv.y > 1.0) {
+		FragColor = vec4(0.0, 0.0, 0.0, 1.0);
+		return;
+	}
+
+	vec4 color = texture(uTexture, uv);
+	float scanline = sin(uv.y * uResolution.y * 3.14159265);
+	color.rgb *= 1.0 - uScanlines * (1.0 - scanline * scanline);
+	FragColor = color;
+}
+`
+
+const vignetteFragmentShader = effectHeader + `
+uniform float uRadius;
+uniform float uSoftness;
+
+void main()
+{
+	vec4 color = texture(uTexture, vTexCoord);
+	float dist = distance(vTexCoord, vec2(0.5)) * 1.41421356;
+	color.rgb *= smoothstep(uRadius, uRadius - uSoftness, dist);
+	FragColor = color;
+}
+`
+
+const colorGradingFragmentShader = effectHeader + `
+uniform sampler2D uLUT;
+uniform float uLUTSize;
+uniform float uAmount;
+
+void main()
+{
+	vec4 color = texture(uTexture, vTexCoord);
+	if (color.a <= 0.0) {
+		FragColor = color;
+		return;
+	}
+	vec3 rgb = clamp(color.rgb / color.a, 0.0, 1.0);
+
+	float last = uLUTSize - 1.0;
+	float blue = rgb.b * last;
+	float slice0 = floor(blue);
+	float slice1 = min(slice0 + 1.0, last);
+	float x = (rgb.r * last + 0.5) / (uLUTSize * uLUTSize);
+	float y = (rgb.g * last + 0.5) / uLUTSize;
+	vec3 graded0 = texture(uLUT, vec2(slice0 / uLUTSize + x, y)).rgb;
+	vec3 graded1 = texture(uLUT, vec2(slice1 / uLUTSize + x, y)).rgb;
+	vec3 graded = mix(graded0, graded1, blue - slice0);
+
+	FragColor = vec4(mix(rgb, graded, uAmount) * color.a, color.a);
+}
+`
+
+// ShaderEffect is an effect drawing a single pass with a fragment shader
+type ShaderEffect struct {
+	pass Pass
+}
+
+// NewEffect creates an effect from a fragment shader. Its uniforms are set
+// with the methods of Shader, the uniforms given by the chain are
+// described by Pass.
+func NewEffect(fragmentShaderSource string) (*ShaderEffect, error) {
+	shader, err := CreateShader(defaultVertexShader, fragmentShaderSource)
+	if err != nil {
+		return nil, err
+	}
+	return NewShaderEffect(shader), nil
+}
+
+// NewShaderEffect creates an effect drawing with a shader, such as one
+// loaded with LoadShader("", path) to be reloaded when watching shaders
+func NewShaderEffect(shader *Shader) *ShaderEffect {
+	return &ShaderEffect{pass: Pass{Shader: shader}}
+}
+
+// Shader returns the shader of the effect
+func (e *ShaderEffect) Shader() *Shader {
+	return e.pass.Shader
+}
+
+// Passes ...
+func (e *ShaderEffect) Passes() []*Pass {
+	return []*Pass{&e.pass}
+}
+
+// Destroy deletes the shader of the effect
+func (e *ShaderEffect) Destroy() {
+	e.pass.Shader.Destroy()
+}
+
+// NewGrayscale creates an effect removing colors, amount goes from 0 for
+// the original colors to 1 for shades of gray. It is the uniform uAmount.
+func NewGrayscale(amount float32) (*ShaderEffect, error) {
+	e, err := NewEffect(grayscaleFragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	e.Shader().SetFloat("uAmount", amount)
+	return e, nil
+}
+
+// NewCRT creates an effect imitating a cathode-ray tube screen with
+// curvature (uniform uCurvature, 0 is flat) and scanlines darkening every
+// other row of pixels (uniform uScanlines, from 0 to 1)
+func NewCRT(curvature, scanlines float32) (*ShaderEffect, error) {
+	e, err := NewEffect(crtFragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	e.Shader().SetFloat("uCurvature", curvature)
+	e.Shader().SetFloat("uScanlines", scanlines)
+	return e, nil
+}
+
+// NewVignette creates an effect darkening the corners. Pixels closer to the
+// center than radius - softness are untouched and pixels further than
+// radius are black, the corners are at a distance of 1. They are the
+// uniforms uRadius and uSoftness.
+func NewVignette(radius, softness float32) (*ShaderEffect, error) {
+	e, err := NewEffect(vignetteFragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	e.Shader().SetFloat("uRadius", radius)
+	e.Shader().SetFloat("uSoftness", softness)
+	return e, nil
+}
+
+// BlurEffect is a gaussian blur drawn in a horizontal and a vertical pass
+type BlurEffect struct {
+	shader *Shader
+	passes [2]Pass
+}
+
+// NewBlur creates a blur spreading pixels over radius pixels
+func NewBlur(radius float32) (*BlurEffect, error) {
+	shader, err := CreateShader(defaultVertexShader, blurFragmentShader)
+	if err != nil {
+		return nil, err
+	}
+	e := &BlurEffect{shader: shader}
+	e.passes = blurPasses(shader)
+	e.SetRadius(radius)
+	return e, nil
+}
+
+func blurPasses(shader *Shader) [2]Pass {
+	return [2]Pass{
+		{Shader: shader, Setup: func(s *Shader) { s.SetFloat2("uDirection", 1, 0) }},
+		{Shader: shader, Setup: func(s *Shader) { s.SetFloat2("uDirection", 0, 1) }},
+	}
+}
+
+// SetRadius changes the distance in pixels over which pixels are spread
+func (e *BlurEffect) SetRadius(radius float32) {
+	e.shader.SetFloat("uRadius", radius)
+}
+
+// Passes ...
+func (e *BlurEffect) Passes() []*Pass {
+	return []*Pass{&e.passes[0], &e.passes[1]}
+}
+
+// Destroy deletes the shader of the effect
+func (e *BlurEffect) Destroy() {
+	e.shader.Destroy()
+}
+
+// BloomEffect makes bright pixels glow: pixels brighter than a threshold
+// are extracted, blurred and added to the image
+type BloomEffect struct {
+	bright, blur, combine *Shader
+	passes                [4]Pass
+}
+
+// NewBloom creates a bloom effect. Pixels brighter than threshold, from 0
+// to 1, glow over radius pixels and the glow is multiplied by intensity.
+func NewBloom(threshold, radius, intensity float32) (*BloomEffect, error) {
+	e := &BloomEffect{}
+	sources := []struct {
+		shader **Shader
+		source string
+	}{
+		{&e.bright, brightFragmentShader},
+		{&e.blur, blurFragmentShader},
+		{&e.combine, bloomFragmentShader},
+	}
+	for _, s := range sources {
+		shader, err := CreateShader(defaultVertexShader, s.source)
+		if err != nil {
+			e.Destroy()
+			return nil, err
+		}
+		*s.shader = shader
+	}
+
+	blur := blurPasses(e.blur)
+	e.passes = [4]Pass{{Shader: e.bright}, blur[0], blur[1], {Shader: e.combine}}
+	e.SetThreshold(threshold)
+	e.SetRadius(radius)
+	e.SetIntensity(intensity)
+	return e, nil
+}
+
+// SetThreshold changes the brightness from which pixels glow
+func (e *BloomEffect) SetThreshold(threshold float32) {
+	e.bright.SetFloat("uThreshold", threshold)
+}
+
+// SetRadius changes the distance in pixels over which the glow spreads
+func (e *BloomEffect) SetRadius(radius float32) {
+	e.blur.SetFloat("uRadius", radius)
+}
+
+// SetIntensity changes the factor multiplying the glow
+func (e *BloomEffect) SetIntensity(intensity float32) {
+	e.combine.SetFloat("uIntensity", intensity)
+}
+
+// Passes ...
+func (e *BloomEffect) Passes() []*Pass {
+	return []*Pass{&e.passes[0], &e.passes[1], &e.passes[2], &e.passes[3]}
+}
+
+// Destroy deletes the shaders of the effect
+func (e *BloomEffect) Destroy() {
+	for _, shader := range []*Shader{e.bright, e.blur, e.combine} {
+		if shader != nil {
+			shader.Destroy()
+		}
+	}
+}
+
+// ColorGradingEffect remaps colors with a lookup table
+type ColorGradingEffect struct {
+	ShaderEffect
+	lut *Texture
+}
+
+// NewColorGrading creates a color grading effect from a lookup table laid
+// out as size slices of size x size pixels side by side, a texture of
+// size*size x size pixels. Red increases to the right of a slice,
+// green downwards and blue from one slice to the next. The effect holds a
+// reference to the texture.
+func NewColorGrading(lut *Texture) (*ColorGradingEffect, error) {
+	e, err := NewEffect(colorGradingFragmentShader)
+	if err != nil {
+		return nil, err
+	}
+
+	lut.Retain()
+	g := &ColorGradingEffect{ShaderEffect: *e, lut: lut}
+	g.pass.Setup = func(s *Shader) {
+		g.lut.Bind(2)
+		s.SetInt("uLUT", 2)
+	}
+	g.Shader().SetFloat("uLUTSize", float32(lut.Height))
+	g.SetAmount(1)
+	return g, nil
+}
+
+// SetAmount blends between the original colors at 0 and the graded colors
+// at 1
+func (e *ColorGradingEffect) SetAmount(amount float32) {
+	e.Shader().SetFloat("uAmount", amount)
+}
+
+// Destroy deletes the shader of the effect and releases the lookup table
+func (e *ColorGradingEffect) Destroy() {
+	e.ShaderEffect.Destroy()
+	e.lut.Release()
+}
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"time"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// Frame is a named region of a texture, usually packed in an atlas
+type Frame struct {
+	Name    string
+	Index   int
+	Texture *Texture
+	// Rect is the region of the texture in pixels, with the size of the
+	// image as displayed. If the frame is rotated, the region occupies
+	// Rect.Height x Rect.Width pixels in the texture.
+	Rect geom.Rect
+	// Rotated frames are stored rotated by 90 degrees in the texture,
+	// clockwise unless CounterClockwise is set
+	Rotated          bool
+	CounterClockwise bool
+	// Offset is the position of the region inside the original image,
+	// whose transparent borders may have been trimmed when packing
+	Offset     geom.Vec2
+	SourceSize geom.Size
+	// Duration is the display time stored by tools like Aseprite, zero
+	// if the atlas doesn't specify it
+	Duration time.Duration
+}
+
+// NewFrame returns a frame covering a whole texture
+func NewFrame(texture *Texture) *Frame {
+	w, h := float32(texture.Width), float32(texture.Height)
+	return &Frame{
+		Index:      -1,
+		Texture:    texture,
+		Rect:       geom.Rect{Width: w, Height: h},
+		SourceSize: geom.Size{Width: w, Height: h},
+	}
+}
+
+// Vertices returns the quad of the frame in the original image coordinates
+// transformed by m, tinted with color
+func (f *Frame) Vertices(m geom.Matrix3, color Color) [4]Vertex {
+	c := color.Premultiplied()
+
+	// Texture coordinates of the region corners as stored in the texture
+	tw, th := float32(f.Texture.Width), float32(f.Texture.Height)
+	sw, sh := f.Rect.Width, f.Rect.Height
+	if f.Rotated {
+		sw, sh = sh, sw
+	}
+	u0, v0 := f.Rect.X/tw, f.Rect.Y/th
+	u1, v1 := (f.Rect.X+sw)/tw, (f.Rect.Y+sh)/th
+	uvs := [4][2]float32{{u0, v0}, {u1, v0}, {u1, v1}, {u0, v1}}
+
+	// Displayed corners in the order top-left, top-right, bottom-right,
+	// bottom-left, mapped to the stored corner showing them
+	order := [4]int{0, 1, 2, 3}
+	if f.Rotated && f.CounterClockwise {
+		order = [4]int{3, 0, 1, 2}
+	} else if f.Rotated {
+		order = [4]int{1, 2, 3, 0}
+	}
+
+	x0, y0 := f.Offset.X, f.Offset.Y
+	x1, y1 := x0+f.Rect.Width, y0+f.Rect.Height
+	corners := [4][2]float32{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}}
+
+	var vertices [4]Vertex
+	for i, corner := range corners {
+		x, y := m.Transform(corner[0], corner[1])
+		uv := uvs[order[i]]
+		vertices[i] = Vertex{x, y, uv[0], uv[1], c.R, c.G, c.B, c.Alpha}
+	}
+	return vertices
+}
+
+// DrawFrame queues a frame transformed by m, with its pixels multiplied by
+// color. The frame is drawn at its offset inside its source size.
+func DrawFrame(frame *Frame, m geom.Matrix3, color Color) {
+	DefaultBatch().DrawQuad(frame.Texture, frame.Vertices(m, color))
+}
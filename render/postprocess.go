@@ -0,0 +1,229 @@
+package render
+
+import (
+	"log"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// Effect is a full-screen effect of a post-processing chain, made of one
+// or more passes drawn in order
+type Effect interface {
+	Passes() []*Pass
+}
+
+// Pass draws the output of the previous pass over the whole target with a
+// shader. The shader receives:
+//
+//	uTexture    sampler2D  output of the previous pass, on texture unit 0
+//	uInput      sampler2D  input of the effect the pass belongs to, on unit 1
+//	uResolution vec2       size of the textures in pixels
+//
+// Colors use premultiplied alpha.
+type Pass struct {
+	Shader *Shader
+	// Setup is called before drawing to set uniforms or bind textures which
+	// differ between passes sharing a shader. Texture units 0 and 1 are
+	// used by the chain.
+	Setup func(shader *Shader)
+}
+
+// PostProcess renders a scene into an off-screen buffer and runs it
+// through a chain of effects before it reaches the screen. The passes
+// alternate between window-sized render targets.
+type PostProcess struct {
+	effects []Effect
+	// buffers[0] receives the scene, the others are used as ping-pong
+	// buffers. One is always free: neither the source of the pass nor the
+	// input of the effect.
+	buffers [3]*RenderTarget
+	batch   *Batch
+	active  bool
+}
+
+// NewPostProcess creates a post-processing chain running effects in order
+func NewPostProcess(effects ...Effect) *PostProcess {
+	return &PostProcess{effects: effects}
+}
+
+// Effects returns the effects of the chain in order
+func (p *PostProcess) Effects() []Effect {
+	return p.effects
+}
+
+// Add appends effects to the chain
+func (p *PostProcess) Add(effects ...Effect) {
+	p.effects = append(p.effects, effects...)
+}
+
+// Insert inserts an effect at a position in the chain
+func (p *PostProcess) Insert(i int, effect Effect) {
+	if i < 0 || i > len(p.effects) {
+		i = len(p.effects)
+	}
+	p.effects = append(p.effects, nil)
+	copy(p.effects[i+1:], p.effects[i:])
+	p.effects[i] = effect
+}
+
+// Remove removes an effect from the chain, it is not destroyed
+func (p *PostProcess) Remove(effect Effect) {
+	for i, e := range p.effects {
+		if e == effect {
+			p.effects = append(p.effects[:i:i], p.effects[i+1:]...)
+			return
+		}
+	}
+}
+
+// Clear removes all the effects from the chain
+func (p *PostProcess) Clear() {
+	p.effects = nil
+}
+
+// Begin redirects drawing to the buffer of the chain until End is called
+func (p *PostProcess) Begin() {
+	if p.active {
+		panic("render: PostProcess.Begin called twice")
+	}
+	if err := p.allocate(); err != nil {
+		log.Println("Failed to begin post-processing:", err)
+		return
+	}
+	p.active = true
+
+	scene := p.buffers[0]
+	scene.Clear(Color{})
+	scene.Begin()
+}
+
+// End runs the effects over what was drawn since Begin and draws the result
+// into the previous target, blended over its content
+func (p *PostProcess) End() {
+	if !p.active {
+		return
+	}
+	p.active = false
+	p.buffers[0].End()
+
+	var passes []effectPass
+	for _, effect := range p.effects {
+		for i, pass := range effect.Passes() {
+			if pass != nil && pass.Shader != nil {
+				passes = append(passes, effectPass{pass, i == 0})
+			}
+		}
+	}
+
+	src := p.buffers[0]
+	input := src
+	for i, ep := range passes {
+		if ep.first {
+			input = src
+		}
+		if i == len(passes)-1 {
+			p.drawOutput(src.Texture, input.Texture, ep.pass)
+			return
+		}
+
+		dst := p.freeBuffer(src, input)
+		dst.Begin()
+		p.draw(src.Texture, input.Texture, ep.pass, BlendNone)
+		dst.End()
+		src = dst
+	}
+
+	p.drawOutput(src.Texture, src.Texture, nil)
+}
+
+// Destroy deletes the buffers of the chain, the effects are not destroyed
+func (p *PostProcess) Destroy() {
+	for i, t := range p.buffers {
+		if t != nil {
+			t.Destroy()
+			p.buffers[i] = nil
+		}
+	}
+	if p.batch != nil {
+		p.batch.Destroy()
+		p.batch = nil
+	}
+}
+
+type effectPass struct {
+	pass *Pass
+	// first is set on the first pass of an effect, whose source is the
+	// input of the effect
+	first bool
+}
+
+func (p *PostProcess) allocate() error {
+	for i, t := range p.buffers {
+		// Buffers are deleted with all the render targets
+		if t == nil || renderTargets[t.id] != t {
+			buffer, err := NewWindowRenderTarget()
+			if err != nil {
+				return err
+			}
+			p.buffers[i] = buffer
+		}
+	}
+	if _, ok := batches[p.batch]; !ok {
+		p.batch = NewBatch(1)
+	}
+	return nil
+}
+
+func (p *PostProcess) freeBuffer(src, input *RenderTarget) *RenderTarget {
+	for _, t := range p.buffers {
+		if t != src && t != input {
+			return t
+		}
+	}
+	return nil
+}
+
+// drawOutput draws the last pass into the previous target
+func (p *PostProcess) drawOutput(texture, input *Texture, pass *Pass) {
+	oldProjection := projection
+	oldViewport := viewport
+
+	p.draw(texture, input, pass, BlendAlpha)
+
+	SetViewport(oldViewport.x, oldViewport.y, oldViewport.width, oldViewport.height)
+	SetProjection(oldProjection)
+}
+
+// draw stretches a texture over the whole bound target, a nil pass copies it
+func (p *PostProcess) draw(texture, input *Texture, pass *Pass, blend BlendMode) {
+	width, height := framebufferWidth, framebufferHeight
+	if n := len(targetStack); n > 0 {
+		t := targetStack[n-1].target
+		width, height = t.Width(), t.Height()
+	}
+	SetViewport(0, 0, width, height)
+
+	// The unit square covers the target with the first row of the texture
+	// at the top, render targets are stored bottom-up
+	m := geom.Ortho(0, 1, 1, 0)
+	if currentTargetID() != 0 {
+		m = geom.Scaling(1, -1).Mul(m)
+	}
+	SetProjection(m)
+
+	var shader *Shader
+	if pass != nil {
+		shader = pass.Shader
+		input.Bind(1)
+		shader.SetInt("uInput", 1)
+		shader.SetFloat2("uResolution", float32(texture.Width), float32(texture.Height))
+		if pass.Setup != nil {
+			pass.Setup(shader)
+		}
+	}
+
+	p.batch.SetShader(shader)
+	p.batch.SetBlendMode(blend)
+	p.batch.DrawQuad(texture, QuadVertices(geom.Identity(), 1, 1, White))
+	p.batch.Flush()
+}
@@ -0,0 +1,33 @@
+package render
+
+import (
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// Vertex is a vertex of a textured quad
+type Vertex struct {
+	X, Y       float32
+	U, V       float32
+	R, G, B, A float32
+}
+
+const vertexSize = 8 * 4
+
+// QuadVertices returns the vertices of a quad covering the rectangle
+// (0, 0) - (width, height) transformed by m, tinted with color
+func QuadVertices(m geom.Matrix3, width, height float32, color Color) [4]Vertex {
+	c := color.Premultiplied()
+	corners := [4][4]float32{
+		{0, 0, 0, 0},
+		{width, 0, 1, 0},
+		{width, height, 1, 1},
+		{0, height, 0, 1},
+	}
+
+	var vertices [4]Vertex
+	for i, corner := range corners {
+		x, y := m.Transform(corner[0], corner[1])
+		vertices[i] = Vertex{x, y, corner[2], corner[3], c.R, c.G, c.B, c.Alpha}
+	}
+	return vertices
+}
@@ -0,0 +1,126 @@
+package render
+
+import (
+	"math"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// ResolutionPolicy decides how a design resolution is fitted into the
+// framebuffer
+type ResolutionPolicy int
+
+const (
+	// ExactFit stretches the design resolution over the whole framebuffer,
+	// the aspect ratio is not kept
+	ExactFit ResolutionPolicy = iota
+	// ShowAll scales the design resolution to fit in the framebuffer and
+	// letterboxes the remaining area
+	ShowAll
+	// NoBorder scales the design resolution to cover the framebuffer, the
+	// edges of the design resolution may be cropped
+	NoBorder
+	// FixedWidth keeps the design width visible, the visible height
+	// follows the aspect ratio of the framebuffer
+	FixedWidth
+	// FixedHeight keeps the design height visible, the visible width
+	// follows the aspect ratio of the framebuffer
+	FixedHeight
+	// PixelPerfect scales the design resolution by the largest integer
+	// factor which fits in the framebuffer and letterboxes the rest, like
+	// ShowAll when the framebuffer is smaller than the design resolution
+	PixelPerfect
+)
+
+var (
+	design struct {
+		width, height float32
+		policy        ResolutionPolicy
+	}
+	pixelRatio float32 = 1
+
+	// visible is the area of the design resolution seen in the viewport
+	visible geom.Rect
+)
+
+// SetDesignResolution makes the projection map a design resolution to the
+// framebuffer following a policy, so that the game looks the same at any
+// window size. A size of 0 maps one unit to one screen coordinate.
+func SetDesignResolution(width, height int, policy ResolutionPolicy) {
+	design.width, design.height = float32(width), float32(height)
+	design.policy = policy
+	applyResolution()
+}
+
+// DesignResolution returns the design resolution and its policy
+func DesignResolution() (width, height int, policy ResolutionPolicy) {
+	return int(design.width), int(design.height), design.policy
+}
+
+// SetPixelRatio sets the number of framebuffer pixels per screen
+// coordinate, which is greater than 1 on HiDPI displays
+func SetPixelRatio(ratio float32) {
+	if ratio <= 0 {
+		ratio = 1
+	}
+	pixelRatio = ratio
+	applyResolution()
+}
+
+// PixelRatio returns the number of framebuffer pixels per screen coordinate
+func PixelRatio() float32 {
+	return pixelRatio
+}
+
+// VisibleRect returns the area of the design resolution visible in the
+// viewport, which differs from the design resolution with the NoBorder,
+// FixedWidth and FixedHeight policies
+func VisibleRect() geom.Rect {
+	return visible
+}
+
+// applyResolution sets the viewport and the projection for the framebuffer
+// size and the design resolution
+func applyResolution() {
+	fbWidth, fbHeight := float32(framebufferWidth), float32(framebufferHeight)
+	if fbWidth == 0 || fbHeight == 0 {
+		// Minimized windows keep their view
+		return
+	}
+	if design.width <= 0 || design.height <= 0 {
+		visible = geom.Rect{Width: fbWidth / pixelRatio, Height: fbHeight / pixelRatio}
+		setView(0, 0, framebufferWidth, framebufferHeight)
+		return
+	}
+
+	scaleX, scaleY := fbWidth/design.width, fbHeight/design.height
+	visible = geom.Rect{Width: design.width, Height: design.height}
+	x, y, width, height := 0, 0, framebufferWidth, framebufferHeight
+
+	switch design.policy {
+	case ShowAll, PixelPerfect:
+		scale := float32(math.Min(float64(scaleX), float64(scaleY)))
+		if design.policy == PixelPerfect && scale >= 1 {
+			scale = float32(math.Floor(float64(scale)))
+		}
+		width = int(design.width * scale)
+		height = int(design.height * scale)
+		x = (framebufferWidth - width) / 2
+		y = (framebufferHeight - height) / 2
+	case NoBorder:
+		scale := float32(math.Max(float64(scaleX), float64(scaleY)))
+		visible.Width, visible.Height = fbWidth/scale, fbHeight/scale
+		visible.X = (design.width - visible.Width) / 2
+		visible.Y = (design.height - visible.Height) / 2
+	case FixedWidth:
+		visible.Height = fbHeight / scaleX
+	case FixedHeight:
+		visible.Width = fbWidth / scaleY
+	}
+	setView(x, y, width, height)
+}
+
+func setView(x, y, width, height int) {
+	SetViewport(x, y, width, height)
+	SetProjection(geom.Ortho(visible.X, visible.X+visible.Width, visible.Y+visible.Height, visible.Y))
+}
@@ -1,7 +1,7 @@
 package render
 
 import (
-	"kiwanoengine.com/kiwano/external/gl"
+	"kiwanoengine.com/kiwano/geom"
 )
 
 var (
@@ -10,29 +10,12 @@ var (
 
 // CreateShader ...
 func CreateShader(vertexShaderSource, fragmentShaderSource string) (*Shader, error) {
-	var (
-		vertexShader   uint32
-		fragmentShader uint32
-		shaderProgram  uint32
-		err            error
-	)
-
-	if vertexShader, err = gl.CreateAndCompileShader(gl.VERTEX_SHADER, vertexShaderSource); err != nil {
+	shaderProgram, err := backend.NewShader(vertexShaderSource, fragmentShaderSource)
+	if err != nil {
 		return nil, err
 	}
-	defer gl.DeleteShader(vertexShader)
 
-	if fragmentShader, err = gl.CreateAndCompileShader(gl.FRAGMENT_SHADER, fragmentShaderSource); err != nil {
-		return nil, err
-	}
-	defer gl.DeleteShader(fragmentShader)
-
-	shaderProgram = gl.CreateProgram()
-	if err = gl.LinkShaderProgram(shaderProgram, vertexShader, fragmentShader); err != nil {
-		return nil, err
-	}
-
-	shader := &Shader{shaderProgram}
+	shader := &Shader{ID: shaderProgram}
 	saveShader(shader.ID, shader)
 	return shader, nil
 }
@@ -43,7 +26,9 @@ func DestroyAllShaders() {
 		for _, s := range shaders {
 			s.Destroy()
 		}
+		shaders = nil
 	}
+	defaultShader = nil
 }
 
 func saveShader(ID uint32, shader *Shader) {
@@ -56,54 +41,94 @@ func saveShader(ID uint32, shader *Shader) {
 // Shader ...
 type Shader struct {
 	ID uint32
+
+	// files is set on shaders loaded from files, which can be reloaded
+	files *shaderFiles
+	// uniforms restores the uniforms of a reloaded shader
+	uniforms map[string]func(shader uint32)
 }
 
 // Use activate the shader
 func (s *Shader) Use() {
-	gl.UseProgram(s.ID)
+	backend.UseShader(s.ID)
 }
 
 // Destroy delete the shader
 func (s *Shader) Destroy() {
-	gl.DeleteProgram(s.ID)
+	backend.DeleteShader(s.ID)
+	delete(shaders, s.ID)
+	s.files = nil
 }
 
 // SetInt ...
 func (s *Shader) SetInt(name string, value int32) {
-	gl.Uniform1i(gl.GetUniformLocation(s.ID, name), value)
+	s.setInt(name, value)
 }
 
 // SetInt2 ...
 func (s *Shader) SetInt2(name string, v0, v1 int32) {
-	gl.Uniform2i(gl.GetUniformLocation(s.ID, name), v0, v1)
+	s.setInt(name, v0, v1)
 }
 
 // SetInt3 ...
 func (s *Shader) SetInt3(name string, v0, v1, v2 int32) {
-	gl.Uniform3i(gl.GetUniformLocation(s.ID, name), v0, v1, v2)
+	s.setInt(name, v0, v1, v2)
 }
 
 // SetInt4 ...
 func (s *Shader) SetInt4(name string, v0, v1, v2, v3 int32) {
-	gl.Uniform4i(gl.GetUniformLocation(s.ID, name), v0, v1, v2, v3)
+	s.setInt(name, v0, v1, v2, v3)
 }
 
 // SetFloat ...
 func (s *Shader) SetFloat(name string, value float32) {
-	gl.Uniform1f(gl.GetUniformLocation(s.ID, name), value)
+	s.setFloat(name, value)
 }
 
 // SetFloat2 ...
 func (s *Shader) SetFloat2(name string, v0, v1 float32) {
-	gl.Uniform2f(gl.GetUniformLocation(s.ID, name), v0, v1)
+	s.setFloat(name, v0, v1)
 }
 
 // SetFloat3 ...
 func (s *Shader) SetFloat3(name string, v0, v1, v2 float32) {
-	gl.Uniform3f(gl.GetUniformLocation(s.ID, name), v0, v1, v2)
+	s.setFloat(name, v0, v1, v2)
 }
 
 // SetFloat4 ...
 func (s *Shader) SetFloat4(name string, v0, v1, v2, v3 float32) {
-	gl.Uniform4f(gl.GetUniformLocation(s.ID, name), v0, v1, v2, v3)
+	s.setFloat(name, v0, v1, v2, v3)
+}
+
+// SetMatrix3 ...
+func (s *Shader) SetMatrix3(name string, m geom.Matrix3) {
+	backend.SetUniformMatrix3(s.ID, name, m)
+	s.keep(name, func(shader uint32) {
+		backend.SetUniformMatrix3(shader, name, m)
+	})
+}
+
+func (s *Shader) setInt(name string, values ...int32) {
+	backend.SetUniformInt(s.ID, name, values...)
+	s.keep(name, func(shader uint32) {
+		backend.SetUniformInt(shader, name, values...)
+	})
+}
+
+func (s *Shader) setFloat(name string, values ...float32) {
+	backend.SetUniformFloat(s.ID, name, values...)
+	s.keep(name, func(shader uint32) {
+		backend.SetUniformFloat(shader, name, values...)
+	})
+}
+
+// keep records how to set a uniform again after a reload
+func (s *Shader) keep(name string, set func(shader uint32)) {
+	if s.files == nil {
+		return
+	}
+	if s.uniforms == nil {
+		s.uniforms = make(map[string]func(shader uint32))
+	}
+	s.uniforms[name] = set
 }
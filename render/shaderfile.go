@@ -0,0 +1,295 @@
+package render
+
+import (
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ShaderStage is a stage of a shader program
+type ShaderStage int
+
+const (
+	VertexStage ShaderStage = iota
+	FragmentStage
+)
+
+func (s ShaderStage) String() string {
+	if s == VertexStage {
+		return "vertex"
+	}
+	return "fragment"
+}
+
+// ShaderError is returned when a stage of a shader fails to compile. For
+// shaders loaded from files, the locations in Log are file:line.
+type ShaderError struct {
+	Stage ShaderStage
+	Log   string
+}
+
+func (e *ShaderError) Error() string {
+	return fmt.Sprintf("Failed to compile %v shader:\n%v", e.Stage, strings.TrimSpace(e.Log))
+}
+
+var (
+	includePattern = regexp.MustCompile(`^\s*#\s*include\s+"([^"]+)"\s*$`)
+	// logLocation matches the locations of the drivers: 0:12(5) with Mesa,
+	// 0(12) with NVIDIA and 0:12 with AMD, 0 being the source string
+	logLocation = regexp.MustCompile(`\b0(?::(\d+)|\((\d+)\))`)
+
+	shaderWatchInterval time.Duration
+	lastShaderWatch     time.Time
+)
+
+// LoadShader compiles a shader from a vertex and a fragment shader file. An
+// empty path selects the stage of the default shader. Files may include
+// other files with #include "path", relative to the including file, and
+// each file is included once.
+func LoadShader(vertexPath, fragmentPath string) (*Shader, error) {
+	return loadShader(&shaderFiles{vertex: vertexPath, fragment: fragmentPath})
+}
+
+// LoadShaderFS is like LoadShader with files read from a file system
+func LoadShaderFS(fsys fs.FS, vertexPath, fragmentPath string) (*Shader, error) {
+	return loadShader(&shaderFiles{fsys: fsys, vertex: vertexPath, fragment: fragmentPath})
+}
+
+func loadShader(files *shaderFiles) (*Shader, error) {
+	id, err := files.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	shader := &Shader{ID: id, files: files}
+	saveShader(shader.ID, shader)
+	return shader, nil
+}
+
+// Reload compiles the files of a shader loaded with LoadShader again and
+// swaps the program in place, uniforms keep their values. The shader is
+// left untouched when compilation fails.
+func (s *Shader) Reload() error {
+	if s.files == nil {
+		return fmt.Errorf("Failed to reload shader %v: not loaded from files", s.ID)
+	}
+
+	id, err := s.files.compile()
+	if err != nil {
+		return err
+	}
+
+	Flush()
+	backend.DeleteShader(s.ID)
+	delete(shaders, s.ID)
+	s.ID = id
+	saveShader(s.ID, s)
+
+	for _, set := range s.uniforms {
+		set(s.ID)
+	}
+	return nil
+}
+
+// WatchShaders reloads the shaders loaded from files when one of their
+// files changes, files are checked at most once per interval from
+// BeginFrame. It is meant for development, 0 stops watching.
+func WatchShaders(interval time.Duration) {
+	shaderWatchInterval = interval
+}
+
+// ReloadChangedShaders reloads the shaders loaded from files whose files
+// changed since they were compiled. Errors are logged and the previous
+// program is kept.
+func ReloadChangedShaders() {
+	for _, shader := range shaders {
+		if shader.files == nil || !shader.files.changed() {
+			continue
+		}
+		if err := shader.Reload(); err != nil {
+			log.Println("Failed to reload shader:", err)
+		} else {
+			log.Println("Reloaded shader", shader.files)
+		}
+	}
+}
+
+// watchShaders is called every frame
+func watchShaders() {
+	if shaderWatchInterval <= 0 || time.Since(lastShaderWatch) < shaderWatchInterval {
+		return
+	}
+	lastShaderWatch = time.Now()
+	ReloadChangedShaders()
+}
+
+// shaderFiles are the files a shader is compiled from, read from fsys or
+// from the OS when it is nil
+type shaderFiles struct {
+	fsys             fs.FS
+	vertex, fragment string
+	// modTimes are the modification times of the files read by the last
+	// compilation, includes too
+	modTimes map[string]time.Time
+}
+
+func (f *shaderFiles) String() string {
+	return strings.TrimSpace(f.vertex + " " + f.fragment)
+}
+
+// compile preprocesses and compiles the files, the files read are watched
+// even when compilation fails so that fixing them triggers a reload
+func (f *shaderFiles) compile() (uint32, error) {
+	f.modTimes = make(map[string]time.Time)
+
+	vertex, err := f.preprocess(f.vertex, defaultVertexShader)
+	if err != nil {
+		return 0, err
+	}
+	fragment, err := f.preprocess(f.fragment, defaultFragmentShader)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := backend.NewShader(vertex.source, fragment.source)
+	if e, ok := err.(*ShaderError); ok {
+		lines := vertex.lines
+		if e.Stage == FragmentStage {
+			lines = fragment.lines
+		}
+		return 0, &ShaderError{Stage: e.Stage, Log: mapShaderLog(e.Log, lines)}
+	}
+	return id, err
+}
+
+func (f *shaderFiles) changed() bool {
+	for name, modTime := range f.modTimes {
+		info, err := f.stat(name)
+		// Editors may remove a file while saving it, check it again later
+		if err == nil && !info.ModTime().Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *shaderFiles) read(name string) ([]byte, error) {
+	if info, err := f.stat(name); err == nil {
+		f.modTimes[name] = info.ModTime()
+	}
+	if f.fsys == nil {
+		return os.ReadFile(name)
+	}
+	return fs.ReadFile(f.fsys, name)
+}
+
+func (f *shaderFiles) stat(name string) (fs.FileInfo, error) {
+	if f.fsys == nil {
+		return os.Stat(name)
+	}
+	return fs.Stat(f.fsys, name)
+}
+
+// resolve returns the path of a file included by another
+func (f *shaderFiles) resolve(from, name string) string {
+	if f.fsys == nil {
+		if filepath.IsAbs(name) {
+			return name
+		}
+		return filepath.Join(filepath.Dir(from), name)
+	}
+	return path.Join(path.Dir(from), name)
+}
+
+// shaderLine is the origin of a line of preprocessed source
+type shaderLine struct {
+	file string
+	line int
+}
+
+type preprocessed struct {
+	source string
+	lines  []shaderLine
+}
+
+// preprocess reads a shader file and expands its includes, an empty name
+// returns the fallback source
+func (f *shaderFiles) preprocess(name, fallback string) (*preprocessed, error) {
+	if name == "" {
+		return &preprocessed{source: fallback}, nil
+	}
+
+	p := &preprocessor{files: f, included: make(map[string]bool)}
+	if err := p.include(name); err != nil {
+		return nil, err
+	}
+	return &preprocessed{source: p.out.String(), lines: p.lines}, nil
+}
+
+type preprocessor struct {
+	files    *shaderFiles
+	included map[string]bool
+	out      strings.Builder
+	lines    []shaderLine
+}
+
+func (p *preprocessor) include(name string) error {
+	if p.included[name] {
+		return nil
+	}
+	p.included[name] = true
+
+	data, err := p.files.read(name)
+	if err != nil {
+		return err
+	}
+
+	text := strings.TrimSuffix(string(data), "\n")
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if m := includePattern.FindStringSubmatch(line); m != nil {
+			if err := p.include(p.files.resolve(name, m[1])); err != nil {
+				return fmt.Errorf("%v:%v: Failed to include %v: %v", name, i+1, m[1], err)
+			}
+			continue
+		}
+		p.out.WriteString(line)
+		p.out.WriteByte('\n')
+		p.lines = append(p.lines, shaderLine{name, i + 1})
+	}
+	return nil
+}
+
+// mapShaderLog replaces the locations of a driver log, which are lines of
+// the preprocessed source, with the files and lines they come from
+func mapShaderLog(log string, lines []shaderLine) string {
+	if len(lines) == 0 {
+		return log
+	}
+
+	logLines := strings.Split(log, "\n")
+	for i, l := range logLines {
+		m := logLocation.FindStringSubmatchIndex(l)
+		if m == nil {
+			continue
+		}
+		start, end := m[2], m[3]
+		if start < 0 {
+			start, end = m[4], m[5]
+		}
+		n, err := strconv.Atoi(l[start:end])
+		if err != nil || n < 1 || n > len(lines) {
+			continue
+		}
+		origin := lines[n-1]
+		logLines[i] = l[:m[0]] + fmt.Sprintf("%v:%v", origin.file, origin.line) + l[m[1]:]
+	}
+	return strings.Join(logLines, "\n")
+}
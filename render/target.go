@@ -0,0 +1,253 @@
+package render
+
+import (
+	"image"
+
+	"kiwanoengine.com/kiwano/geom"
+)
+
+// RenderTarget is an off-screen framebuffer drawing into a texture, which
+// can then be drawn like any texture
+type RenderTarget struct {
+	Texture *Texture
+
+	id           uint32
+	followWindow bool
+	onResize     func(width, height int)
+}
+
+// targetState is the drawing state restored by RenderTarget.End
+type targetState struct {
+	target     *RenderTarget
+	projection geom.Matrix3
+	viewport   struct{ x, y, width, height int }
+}
+
+var (
+	renderTargets map[uint32]*RenderTarget
+	targetStack   []targetState
+)
+
+// NewRenderTarget creates a render target of a fixed size in pixels. The
+// origin is at the top-left corner and one unit is one pixel.
+func NewRenderTarget(width, height int) (*RenderTarget, error) {
+	t := &RenderTarget{}
+	if err := t.allocate(width, height); err != nil {
+		return nil, err
+	}
+	saveRenderTarget(t)
+	return t, nil
+}
+
+// NewWindowRenderTarget creates a render target of the size of the
+// framebuffer, which is resized with the window. Draws use the projection
+// of the window, so they land where they would on screen.
+func NewWindowRenderTarget() (*RenderTarget, error) {
+	t, err := NewRenderTarget(framebufferWidth, framebufferHeight)
+	if err != nil {
+		return nil, err
+	}
+	t.followWindow = true
+	return t, nil
+}
+
+// DestroyAllRenderTargets ...
+func DestroyAllRenderTargets() {
+	for _, t := range renderTargets {
+		t.Destroy()
+	}
+	renderTargets = nil
+	targetStack = nil
+}
+
+func saveRenderTarget(t *RenderTarget) {
+	if renderTargets == nil {
+		renderTargets = make(map[uint32]*RenderTarget)
+	}
+	renderTargets[t.id] = t
+}
+
+// resizeRenderTargets resizes the render targets following the window
+func resizeRenderTargets(width, height int) {
+	for _, t := range renderTargets {
+		if t.followWindow {
+			t.Resize(width, height)
+		}
+	}
+}
+
+func (t *RenderTarget) allocate(width, height int) error {
+	if width <= 0 || height <= 0 {
+		width, height = 1, 1
+	}
+
+	texture := NewTexture(image.NewRGBA(image.Rect(0, 0, width, height)))
+	id, err := backend.NewRenderTarget(texture.ID)
+	if err != nil {
+		texture.Destroy()
+		return err
+	}
+
+	t.Texture = texture
+	t.id = id
+	return nil
+}
+
+// Width returns the width of the render target in pixels
+func (t *RenderTarget) Width() int {
+	return t.Texture.Width
+}
+
+// Height returns the height of the render target in pixels
+func (t *RenderTarget) Height() int {
+	return t.Texture.Height
+}
+
+// Resize reallocates the render target with a new size, its content is
+// lost. The texture keeps its identity, so sprites drawing it keep drawing
+// the render target. Frames store regions in pixels, use OnResize to
+// update frames covering the whole texture.
+func (t *RenderTarget) Resize(width, height int) error {
+	if width == t.Width() && height == t.Height() {
+		return nil
+	}
+
+	Flush()
+	oldID, texture := t.id, t.Texture
+	if err := t.allocate(width, height); err != nil {
+		return err
+	}
+	delete(renderTargets, oldID)
+	saveRenderTarget(t)
+	backend.DeleteRenderTarget(oldID)
+
+	// Move the new storage into the texture held by sprites and frames
+	allocated := t.Texture
+	delete(textures, allocated.ID)
+	delete(textures, texture.ID)
+	backend.DeleteTexture(texture.ID)
+	texture.ID, texture.Width, texture.Height = allocated.ID, allocated.Width, allocated.Height
+	backend.SetTextureOptions(texture.ID, texture.options)
+	saveTexture(texture)
+	t.Texture = texture
+
+	if t.bound() {
+		backend.BindRenderTarget(t.id)
+	}
+	if t.onResize != nil {
+		t.onResize(width, height)
+	}
+	return nil
+}
+
+// OnResize sets a function called after the render target is resized, for
+// render targets following the window too
+func (t *RenderTarget) OnResize(fn func(width, height int)) {
+	t.onResize = fn
+}
+
+// Begin redirects drawing to the render target until End is called. Calls
+// may be nested.
+func (t *RenderTarget) Begin() {
+	Flush()
+	state := targetState{target: t, projection: projection, viewport: viewport}
+	targetStack = append(targetStack, state)
+	backend.BindRenderTarget(t.id)
+
+	// Textures are stored bottom-up, flip the projection so that the top
+	// of the drawing is the first row of the texture like loaded images
+	flip := geom.Scaling(1, -1)
+	if t.followWindow {
+		backend.Viewport(viewport.x, viewport.y, viewport.width, viewport.height)
+		SetProjection(flip.Mul(projection))
+	} else {
+		SetViewport(0, 0, t.Width(), t.Height())
+		SetProjection(flip.Mul(geom.Ortho(0, float32(t.Width()), float32(t.Height()), 0)))
+	}
+}
+
+// End draws what was queued since Begin into the render target and
+// restores the previous target
+func (t *RenderTarget) End() {
+	if len(targetStack) == 0 || targetStack[len(targetStack)-1].target != t {
+		panic("render: RenderTarget.End called without a matching Begin")
+	}
+
+	Flush()
+	state := targetStack[len(targetStack)-1]
+	targetStack = targetStack[:len(targetStack)-1]
+
+	var id uint32
+	if n := len(targetStack); n > 0 {
+		id = targetStack[n-1].target.id
+	}
+	backend.BindRenderTarget(id)
+	vp := state.viewport
+	SetViewport(vp.x, vp.y, vp.width, vp.height)
+	SetProjection(state.projection)
+}
+
+// Clear fills the render target with a color
+func (t *RenderTarget) Clear(color Color) {
+	Flush()
+	backend.BindRenderTarget(t.id)
+	backend.Clear(color)
+	if !t.bound() {
+		backend.BindRenderTarget(currentTargetID())
+	}
+}
+
+// ReadPixels reads the content of the render target back, pixels use
+// premultiplied alpha
+func (t *RenderTarget) ReadPixels() *image.RGBA {
+	Flush()
+	backend.BindRenderTarget(t.id)
+	// The first row of the texture is the top of the drawing
+	img := backend.ReadPixels(0, 0, t.Width(), t.Height())
+	backend.BindRenderTarget(currentTargetID())
+	return img
+}
+
+// Destroy deletes the render target and releases its texture
+func (t *RenderTarget) Destroy() {
+	if t.id == 0 {
+		return
+	}
+	delete(renderTargets, t.id)
+	backend.DeleteRenderTarget(t.id)
+	t.Texture.Release()
+	t.id = 0
+}
+
+// bound reports whether the render target is the current target
+func (t *RenderTarget) bound() bool {
+	return currentTargetID() == t.id
+}
+
+func currentTargetID() uint32 {
+	if n := len(targetStack); n > 0 {
+		return targetStack[n-1].target.id
+	}
+	return 0
+}
+
+// ReadScreen reads the content of the framebuffer of the window back,
+// pixels use premultiplied alpha
+func ReadScreen() *image.RGBA {
+	Flush()
+	backend.BindRenderTarget(0)
+	img := backend.ReadPixels(0, 0, framebufferWidth, framebufferHeight)
+	backend.BindRenderTarget(currentTargetID())
+
+	// OpenGL returns the bottom row first
+	stride := img.Stride
+	row := make([]uint8, stride)
+	for y := 0; y < img.Rect.Dy()/2; y++ {
+		top := img.Pix[y*stride : (y+1)*stride]
+		bottom := img.Pix[(img.Rect.Dy()-1-y)*stride : (img.Rect.Dy()-y)*stride]
+		copy(row, top)
+		copy(top, bottom)
+		copy(bottom, row)
+	}
+	return img
+}
@@ -0,0 +1,226 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"os"
+
+	// Register decoders for the supported image formats
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// LoadImage decodes a PNG or JPEG file into premultiplied RGBA pixels
+func LoadImage(path string) (*image.RGBA, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return toRGBA(img), nil
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Rect.Min == (image.Point{}) {
+		return rgba
+	}
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return rgba
+}
+
+var (
+	textures     map[uint32]*Texture
+	textureCache map[string]*Texture
+)
+
+// TextureFilter selects how texels are interpolated
+type TextureFilter int
+
+const (
+	FilterLinear TextureFilter = iota
+	FilterNearest
+)
+
+// TextureWrap selects how texture coordinates outside [0, 1] are handled
+type TextureWrap int
+
+const (
+	WrapClamp TextureWrap = iota
+	WrapRepeat
+	WrapMirroredRepeat
+)
+
+// TextureOptions are the sampling parameters of a texture
+type TextureOptions struct {
+	Filter TextureFilter
+	Wrap   TextureWrap
+	// Mipmaps generates mipmaps and uses them when the texture is minified
+	Mipmaps bool
+}
+
+// Texture is an image uploaded to the GPU
+type Texture struct {
+	ID            uint32
+	Width, Height int
+
+	options TextureOptions
+	path    string
+	refs    int
+}
+
+// NewTexture uploads an image to the GPU
+func NewTexture(img image.Image) *Texture {
+	return NewTextureWithOptions(img, TextureOptions{})
+}
+
+// NewTextureWithOptions uploads an image to the GPU with sampling options
+func NewTextureWithOptions(img image.Image, options TextureOptions) *Texture {
+	rgba := toRGBA(img)
+	t := &Texture{
+		ID:      backend.NewTexture(rgba, options),
+		Width:   rgba.Rect.Dx(),
+		Height:  rgba.Rect.Dy(),
+		options: options,
+		refs:    1,
+	}
+	saveTexture(t)
+	return t
+}
+
+// LoadTexture returns the texture of an image file. Textures are cached by
+// path and reference counted, call Release when the texture is not used
+// anymore. It fails before a backend is set.
+func LoadTexture(path string) (*Texture, error) {
+	if t, ok := textureCache[path]; ok {
+		t.refs++
+		return t, nil
+	}
+	if backend == nil {
+		return nil, fmt.Errorf("Failed to load texture %v: no render backend set", path)
+	}
+
+	img, err := LoadImage(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTexture(img)
+	t.path = path
+	if textureCache == nil {
+		textureCache = make(map[string]*Texture)
+	}
+	textureCache[path] = t
+	return t, nil
+}
+
+// DestroyAllTextures ...
+func DestroyAllTextures() {
+	for _, t := range textures {
+		t.Destroy()
+	}
+	textures = nil
+	textureCache = nil
+	whiteTexture = nil
+}
+
+var whiteTexture *Texture
+
+// WhiteTexture returns a 1x1 white texture, which draws solid colors when
+// tinted
+func WhiteTexture() *Texture {
+	if whiteTexture == nil {
+		img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		copy(img.Pix, []uint8{255, 255, 255, 255})
+		whiteTexture = NewTexture(img)
+	}
+	return whiteTexture
+}
+
+func saveTexture(t *Texture) {
+	if textures == nil {
+		textures = make(map[uint32]*Texture)
+	}
+	textures[t.ID] = t
+}
+
+// Path returns the file the texture was loaded from, if any
+func (t *Texture) Path() string {
+	return t.path
+}
+
+// Options returns the sampling options of the texture
+func (t *Texture) Options() TextureOptions {
+	return t.options
+}
+
+// SetOptions changes the sampling options of the texture
+func (t *Texture) SetOptions(options TextureOptions) {
+	if t.options == options {
+		return
+	}
+	// Quads already queued with this texture must keep the old options
+	Flush()
+	t.options = options
+	backend.SetTextureOptions(t.ID, options)
+}
+
+// SetFilter changes how texels are interpolated
+func (t *Texture) SetFilter(filter TextureFilter) {
+	options := t.options
+	options.Filter = filter
+	t.SetOptions(options)
+}
+
+// SetWrap changes how coordinates outside the texture are handled
+func (t *Texture) SetWrap(wrap TextureWrap) {
+	options := t.options
+	options.Wrap = wrap
+	t.SetOptions(options)
+}
+
+// GenerateMipmaps generates mipmaps and uses them for minification
+func (t *Texture) GenerateMipmaps() {
+	options := t.options
+	options.Mipmaps = true
+	t.SetOptions(options)
+}
+
+// Retain adds a reference to the texture
+func (t *Texture) Retain() {
+	t.refs++
+}
+
+// Release removes a reference to the texture, it is destroyed when no
+// reference is left
+func (t *Texture) Release() {
+	if t.refs <= 0 {
+		return
+	}
+	t.refs--
+	if t.refs == 0 {
+		t.Destroy()
+	}
+}
+
+// Bind binds the texture to a texture unit
+func (t *Texture) Bind(unit uint32) {
+	backend.BindTexture(unit, t.ID)
+}
+
+// Destroy delete the texture
+func (t *Texture) Destroy() {
+	backend.DeleteTexture(t.ID)
+	delete(textures, t.ID)
+	if t.path != "" && textureCache[t.path] == t {
+		delete(textureCache, t.path)
+	}
+	t.refs = 0
+}
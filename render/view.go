@@ -0,0 +1,81 @@
+package render
+
+import "kiwanoengine.com/kiwano/geom"
+
+var (
+	projection = geom.Identity()
+	viewport   struct{ x, y, width, height int }
+
+	framebufferWidth, framebufferHeight int
+)
+
+// Resize sets the viewport and the projection for a framebuffer size. The
+// origin is at the top-left corner and one unit is one screen coordinate,
+// unless a design resolution is set.
+func Resize(width, height int) {
+	framebufferWidth, framebufferHeight = width, height
+	applyResolution()
+	if width > 0 && height > 0 {
+		resizeRenderTargets(width, height)
+	}
+}
+
+// FramebufferSize returns the size of the framebuffer given to Resize
+func FramebufferSize() (int, int) {
+	return framebufferWidth, framebufferHeight
+}
+
+// SetViewport changes the area of the framebuffer drawn into, the origin
+// is at the bottom-left corner like in OpenGL
+func SetViewport(x, y, width, height int) {
+	Flush()
+	viewport.x, viewport.y = x, y
+	viewport.width, viewport.height = width, height
+	backend.Viewport(x, y, width, height)
+}
+
+// Viewport returns the area of the framebuffer drawn into
+func Viewport() (x, y, width, height int) {
+	return viewport.x, viewport.y, viewport.width, viewport.height
+}
+
+// Projection returns the matrix mapping world coordinates to normalized
+// device coordinates
+func Projection() geom.Matrix3 {
+	return projection
+}
+
+// SetProjection changes the matrix mapping world coordinates to normalized
+// device coordinates
+func SetProjection(m geom.Matrix3) {
+	// Queued quads must be drawn with the projection they were queued with
+	Flush()
+	projection = m
+}
+
+// Unproject converts a framebuffer pixel, with the origin at the top-left
+// corner, to world coordinates
+func Unproject(x, y float32) geom.Vec2 {
+	if viewport.width == 0 || viewport.height == 0 {
+		return geom.Vec2{}
+	}
+
+	glY := float32(framebufferHeight) - y
+	ndcX := (x-float32(viewport.x))/float32(viewport.width)*2 - 1
+	ndcY := (glY-float32(viewport.y))/float32(viewport.height)*2 - 1
+
+	inv, ok := projection.Invert()
+	if !ok {
+		return geom.Vec2{}
+	}
+	return inv.TransformVec2(geom.Vec2{X: ndcX, Y: ndcY})
+}
+
+// Project converts a point in world coordinates to a framebuffer pixel, with
+// the origin at the top-left corner
+func Project(p geom.Vec2) (float32, float32) {
+	ndc := projection.TransformVec2(p)
+	x := float32(viewport.x) + (ndc.X+1)/2*float32(viewport.width)
+	glY := float32(viewport.y) + (ndc.Y+1)/2*float32(viewport.height)
+	return x, float32(framebufferHeight) - glY
+}
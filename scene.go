@@ -1,9 +1,182 @@
 package kiwano
 
-import "time"
+import (
+	"time"
+
+	"kiwanoengine.com/kiwano/node"
+	"kiwanoengine.com/kiwano/render"
+)
 
 type Scene interface {
 	OnEnter()
 	OnExit()
 	OnUpdate(time.Duration)
 }
+
+// NodeScene is implemented by scenes owning a node tree, MainLoop updates
+// and renders the tree of the current scene every frame
+type NodeScene interface {
+	Scene
+	Root() node.Node
+}
+
+// CameraScene is implemented by scenes rendered through a camera. The
+// camera is updated after the node tree of the scene.
+type CameraScene interface {
+	Camera() *node.Camera
+}
+
+// sceneCamera returns the camera of a scene, or nil
+func sceneCamera(scene Scene) *node.Camera {
+	if s, ok := scene.(CameraScene); ok {
+		return s.Camera()
+	}
+	return nil
+}
+
+// PostProcessScene is implemented by scenes rendered through a
+// post-processing chain. The chain of the camera of the scene, if any, runs
+// first.
+type PostProcessScene interface {
+	PostProcess() *render.PostProcess
+}
+
+// scenePostProcess returns the post-processing chain of a scene, or nil
+func scenePostProcess(scene Scene) *render.PostProcess {
+	if s, ok := scene.(PostProcessScene); ok {
+		return s.PostProcess()
+	}
+	return nil
+}
+
+// Renderer is implemented by scenes drawing after their node tree every
+// frame. alpha is the interpolation factor returned by InterpolationAlpha.
+type Renderer interface {
+	OnRender(alpha float32)
+}
+
+// Pausable is implemented by scenes notified when they stop being updated,
+// because a scene is pushed over them or the game is paused, and when they
+// are updated again
+type Pausable interface {
+	OnPause()
+	OnResume()
+}
+
+// Resizable is implemented by scenes notified when the framebuffer of the
+// main window is resized
+type Resizable interface {
+	OnResize(width, height int)
+}
+
+// FocusAware is implemented by scenes notified when the main window gains
+// or loses the input focus
+type FocusAware interface {
+	OnFocusChange(focused bool)
+}
+
+// IconifyAware is implemented by scenes notified when the main window is
+// minimized or restored
+type IconifyAware interface {
+	OnIconify(iconified bool)
+}
+
+// MoveAware is implemented by scenes notified when the main window moves,
+// x and y are the position of its client area on the desktop
+type MoveAware interface {
+	OnMove(x, y int)
+}
+
+// CloseHandler is implemented by scenes asked before the main window is
+// closed by the user, returning false keeps the window open
+type CloseHandler interface {
+	OnCloseRequested() bool
+}
+
+func pauseScene(scene Scene) {
+	if s, ok := scene.(Pausable); ok {
+		s.OnPause()
+	}
+}
+
+func resumeScene(scene Scene) {
+	if s, ok := scene.(Pausable); ok {
+		s.OnResume()
+	}
+}
+
+// BaseScene is a scene with a root node. Embed it into a struct and add
+// nodes to it instead of drawing everything in OnUpdate.
+type BaseScene struct {
+	root        *node.NodeProperties
+	camera      *node.Camera
+	postProcess *render.PostProcess
+}
+
+// Root returns the root node of the scene
+func (s *BaseScene) Root() node.Node {
+	return s.rootNode()
+}
+
+// AddChild adds a node to the root of the scene
+func (s *BaseScene) AddChild(child node.Node) {
+	s.rootNode().AddChild(child)
+}
+
+// RemoveChild removes a node from the root of the scene
+func (s *BaseScene) RemoveChild(child node.Node) {
+	s.rootNode().RemoveChild(child)
+}
+
+// After calls fn once after a delay. The timer pauses with the scene and is
+// cancelled when the scene exits.
+func (s *BaseScene) After(delay time.Duration, fn func()) *node.Timer {
+	return s.rootNode().After(delay, fn)
+}
+
+// Every calls fn repeatedly at an interval until the timer is stopped or
+// the scene exits
+func (s *BaseScene) Every(interval time.Duration, fn func()) *node.Timer {
+	return s.rootNode().Every(interval, fn)
+}
+
+// Camera returns the camera of the scene, nil by default
+func (s *BaseScene) Camera() *node.Camera {
+	return s.camera
+}
+
+// SetCamera makes the scene render through a camera, nil removes it
+func (s *BaseScene) SetCamera(camera *node.Camera) {
+	s.camera = camera
+}
+
+// PostProcess returns the post-processing chain of the scene, nil by
+// default
+func (s *BaseScene) PostProcess() *render.PostProcess {
+	return s.postProcess
+}
+
+// SetPostProcess runs the rendering of the scene through a post-processing
+// chain, nil removes it
+func (s *BaseScene) SetPostProcess(p *render.PostProcess) {
+	s.postProcess = p
+}
+
+func (s *BaseScene) rootNode() *node.NodeProperties {
+	if s.root == nil {
+		s.root = node.New()
+	}
+	return s.root
+}
+
+// OnEnter does nothing by default
+func (s *BaseScene) OnEnter() {
+}
+
+// OnExit does nothing by default
+func (s *BaseScene) OnExit() {
+}
+
+// OnUpdate does nothing by default
+func (s *BaseScene) OnUpdate(dt time.Duration) {
+}
@@ -0,0 +1,246 @@
+package kiwano
+
+import (
+	"time"
+
+	"kiwanoengine.com/kiwano/node"
+	"kiwanoengine.com/kiwano/render"
+)
+
+// stackedScene is a scene of the stack, overlays let the scene below them
+// render
+type stackedScene struct {
+	scene   Scene
+	overlay bool
+}
+
+var sceneStack []stackedScene
+
+// Scenes returns the scene stack, from the bottom to CurrentScene
+func Scenes() []Scene {
+	scenes := make([]Scene, len(sceneStack))
+	for i, s := range sceneStack {
+		scenes[i] = s.scene
+	}
+	return scenes
+}
+
+// EnterScene exits all the scenes of the stack and enters a new scene
+func EnterScene(scene Scene) {
+	EnterSceneWithTransition(scene, nil)
+}
+
+// EnterSceneWithTransition enters a new scene like EnterScene, animating the
+// change with a transition. The new scene is entered at once and the old
+// scenes exit when the transition ends.
+func EnterSceneWithTransition(scene Scene, t Transition) {
+	finishTransition()
+
+	old := sceneStack
+	sceneStack = nil
+	change(t, scene, func() {
+		for i := len(old) - 1; i >= 0; i-- {
+			exitScene(old[i].scene)
+		}
+	}, func() {
+		if scene != nil {
+			sceneStack = []stackedScene{{scene: scene}}
+		}
+	})
+}
+
+// PushScene enters a scene on top of the current one, which is paused and
+// stops being rendered until the new scene is popped
+func PushScene(scene Scene) {
+	PushSceneWithTransition(scene, nil)
+}
+
+// PushOverlay enters a scene on top of the current one, which is paused
+// but still rendered below the new scene, like under a pause menu
+func PushOverlay(scene Scene) {
+	finishTransition()
+	push(stackedScene{scene, true}, nil)
+}
+
+// PushSceneWithTransition pushes a scene like PushScene, animating the
+// change with a transition
+func PushSceneWithTransition(scene Scene, t Transition) {
+	finishTransition()
+	push(stackedScene{scene, false}, t)
+}
+
+func push(s stackedScene, t Transition) {
+	if s.scene == nil {
+		return
+	}
+	change(t, s.scene, nil, func() {
+		sceneStack = append(sceneStack, s)
+	})
+}
+
+// PopScene exits the current scene and resumes the scene below it
+func PopScene() {
+	PopSceneWithTransition(nil)
+}
+
+// PopSceneWithTransition pops the current scene like PopScene, animating
+// the change with a transition. The scene exits when the transition ends.
+func PopSceneWithTransition(t Transition) {
+	finishTransition()
+	if len(sceneStack) == 0 {
+		return
+	}
+
+	top := sceneStack[len(sceneStack)-1].scene
+	change(t, nil, func() { exitScene(top) }, func() {
+		sceneStack = sceneStack[:len(sceneStack)-1]
+	})
+}
+
+// change updates the stack and enters the entering scene right away, then
+// calls done at the end of the transition, or at once if t is nil. A scene
+// covered by the new CurrentScene is paused and a scene revealed by it is
+// resumed, unless the game is paused: scenes becoming current then stay
+// paused until Resume.
+func change(t Transition, entering Scene, done func(), update func()) {
+	from := visibleScenes()
+	top := CurrentScene
+
+	update()
+	CurrentScene = nil
+	if len(sceneStack) > 0 {
+		CurrentScene = sceneStack[len(sceneStack)-1].scene
+	}
+	current := CurrentScene
+	if current != top {
+		accumulator = 0
+		// While the game is paused, the old scene was already paused
+		if top != nil && !paused && inStack(top) {
+			pauseScene(top)
+		}
+	}
+
+	if t == nil || t.Duration() <= 0 {
+		if done != nil {
+			done()
+		}
+	} else {
+		transition = &activeTransition{t: t, from: from, done: done}
+	}
+
+	// Without a transition the old scene has already exited
+	if entering != nil {
+		entering.OnEnter()
+	}
+
+	if current == top || current == nil {
+		return
+	}
+	if current == entering {
+		if paused {
+			pauseScene(current)
+		}
+	} else if !paused {
+		resumeScene(current)
+	}
+}
+
+// inStack reports whether a scene is in the scene stack
+func inStack(scene Scene) bool {
+	for _, s := range sceneStack {
+		if s.scene == scene {
+			return true
+		}
+	}
+	return false
+}
+
+// exitScene exits a scene and cancels the actions and timers of its nodes
+// and its camera
+func exitScene(scene Scene) {
+	scene.OnExit()
+	if s, ok := scene.(NodeScene); ok {
+		node.StopActions(s.Root())
+	}
+	if camera := sceneCamera(scene); camera != nil {
+		node.StopActions(camera)
+	}
+}
+
+// visibleScenes returns the scenes to render, from the bottom
+func visibleScenes() []Scene {
+	i := len(sceneStack) - 1
+	for i > 0 && sceneStack[i].overlay {
+		i--
+	}
+	if i < 0 {
+		return nil
+	}
+	return Scenes()[i:]
+}
+
+// updateScene runs the fixed updates and the update of a scene
+func updateScene(scene Scene, dt time.Duration) {
+	fixedUpdate(scene, dt)
+	scene.OnUpdate(dt)
+
+	if s, ok := scene.(NodeScene); ok {
+		node.Update(s.Root(), dt)
+	}
+	if camera := sceneCamera(scene); camera != nil {
+		node.Update(camera, dt)
+	}
+}
+
+// opacityNode is implemented by nodes embedding node.NodeProperties
+type opacityNode interface {
+	Opacity() float32
+	SetOpacity(float32)
+}
+
+// renderScenes draws scenes from the bottom through their camera, with the
+// opacity multiplied into their node tree
+func renderScenes(scenes []Scene, opacity float32) {
+	for _, scene := range scenes {
+		post := scenePostProcess(scene)
+		if post != nil {
+			post.Begin()
+		}
+
+		camera := sceneCamera(scene)
+		projection := render.Projection()
+		var cameraPost *render.PostProcess
+		if camera != nil {
+			render.SetProjection(camera.ViewProjection())
+			if cameraPost = camera.PostProcess(); cameraPost != nil {
+				cameraPost.Begin()
+			}
+		}
+
+		if s, ok := scene.(NodeScene); ok {
+			root := s.Root()
+			if n, ok := root.(opacityNode); ok && opacity != 1 {
+				old := n.Opacity()
+				n.SetOpacity(old * opacity)
+				node.Render(root)
+				n.SetOpacity(old)
+			} else {
+				node.Render(root)
+			}
+		}
+
+		if r, ok := scene.(Renderer); ok {
+			r.OnRender(InterpolationAlpha())
+		}
+
+		if cameraPost != nil {
+			cameraPost.End()
+		}
+		if camera != nil {
+			render.SetProjection(projection)
+		}
+		if post != nil {
+			post.End()
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package kiwano
+
+import "time"
+
+// Defaults of the fixed update tick
+const (
+	DefaultTickRate      = 60
+	DefaultMaxFixedSteps = 5
+)
+
+// FixedUpdater is implemented by scenes with logic which must run at a
+// constant rate, like physics. OnFixedUpdate is called zero or more times
+// per frame with FixedDelta, before OnUpdate.
+type FixedUpdater interface {
+	OnFixedUpdate(dt time.Duration)
+}
+
+var (
+	fixedDelta    = time.Second / DefaultTickRate
+	maxFixedSteps = DefaultMaxFixedSteps
+	accumulator   time.Duration
+)
+
+// SetTickRate changes the number of fixed updates per second
+func SetTickRate(hz int) {
+	if hz <= 0 {
+		hz = DefaultTickRate
+	}
+	fixedDelta = time.Second / time.Duration(hz)
+	accumulator = 0
+}
+
+// FixedDelta returns the duration of a fixed update tick
+func FixedDelta() time.Duration {
+	return fixedDelta
+}
+
+// SetMaxFixedSteps limits the number of fixed updates run in one frame.
+// When a frame takes longer the remaining time is dropped, so that a slow
+// machine is not stuck catching up with the simulation.
+func SetMaxFixedSteps(steps int) {
+	if steps <= 0 {
+		steps = DefaultMaxFixedSteps
+	}
+	maxFixedSteps = steps
+}
+
+// InterpolationAlpha returns how far the current frame is between the last
+// fixed update and the next one, in [0, 1). Renderers blend the previous
+// and current physics state with it to move smoothly at any frame rate.
+func InterpolationAlpha() float32 {
+	return float32(accumulator) / float32(fixedDelta)
+}
+
+// fixedUpdate runs the fixed updates due after a frame of dt
+func fixedUpdate(scene Scene, dt time.Duration) {
+	accumulator += dt
+
+	updater, _ := scene.(FixedUpdater)
+	for steps := 0; accumulator >= fixedDelta; steps++ {
+		if steps == maxFixedSteps {
+			// Spiral of death protection, drop the ticks we can't run
+			accumulator %= fixedDelta
+			break
+		}
+		if updater != nil {
+			updater.OnFixedUpdate(fixedDelta)
+		}
+		accumulator -= fixedDelta
+	}
+}
@@ -0,0 +1,166 @@
+package kiwano
+
+import (
+	"time"
+
+	"kiwanoengine.com/kiwano/geom"
+	"kiwanoengine.com/kiwano/render"
+)
+
+// Transition animates a scene change, both scenes are rendered until it ends
+type Transition interface {
+	Duration() time.Duration
+	// Render draws a frame of the transition, progress goes from 0 to 1.
+	// from and to render the outgoing and the incoming scenes with their
+	// node trees faded by opacity.
+	Render(progress float32, from, to func(opacity float32))
+}
+
+type activeTransition struct {
+	t       Transition
+	from    []Scene
+	done    func()
+	elapsed time.Duration
+}
+
+var transition *activeTransition
+
+// InTransition reports whether a scene transition is running
+func InTransition() bool {
+	return transition != nil
+}
+
+// renderTransition draws the running transition after dt has elapsed
+func renderTransition(dt time.Duration) {
+	tr := transition
+	tr.elapsed += dt
+	progress := float32(tr.elapsed) / float32(tr.t.Duration())
+	if progress > 1 {
+		progress = 1
+	}
+
+	tr.t.Render(progress, func(opacity float32) {
+		renderScenes(tr.from, opacity)
+	}, func(opacity float32) {
+		renderScenes(visibleScenes(), opacity)
+	})
+
+	if progress == 1 {
+		finishTransition()
+	}
+}
+
+// finishTransition ends the running transition at once
+func finishTransition() {
+	if tr := transition; tr != nil {
+		transition = nil
+		if tr.done != nil {
+			tr.done()
+		}
+	}
+}
+
+// fillScreen covers the whole viewport with a color
+func fillScreen(color Color) {
+	projection := render.Projection()
+	render.SetProjection(geom.Identity())
+	render.FillRect(geom.Translation(-1, -1), 2, 2, color)
+	render.SetProjection(projection)
+}
+
+// withOffset renders with the view moved by (x, y) screens
+func withOffset(x, y float32, draw func()) {
+	projection := render.Projection()
+	render.SetProjection(geom.Translation(x*2, y*2).Mul(projection))
+	draw()
+	render.SetProjection(projection)
+}
+
+type fadeTransition struct {
+	duration time.Duration
+	color    Color
+}
+
+// Fade returns a transition fading the old scene out to a color during the
+// first half of d, then fading the new scene in
+func Fade(d time.Duration, color Color) Transition {
+	return &fadeTransition{d, color}
+}
+
+func (t *fadeTransition) Duration() time.Duration {
+	return t.duration
+}
+
+func (t *fadeTransition) Render(progress float32, from, to func(float32)) {
+	color := t.color
+	if progress < 0.5 {
+		from(1)
+		color.Alpha *= progress * 2
+	} else {
+		to(1)
+		color.Alpha *= (1 - progress) * 2
+	}
+	fillScreen(color)
+}
+
+type crossfadeTransition struct {
+	duration time.Duration
+}
+
+// Crossfade returns a transition fading the new scene in over the old one
+func Crossfade(d time.Duration) Transition {
+	return &crossfadeTransition{d}
+}
+
+func (t *crossfadeTransition) Duration() time.Duration {
+	return t.duration
+}
+
+func (t *crossfadeTransition) Render(progress float32, from, to func(float32)) {
+	from(1)
+	to(progress)
+}
+
+// SlideDirection is the direction in which a slide transition moves the
+// scenes
+type SlideDirection int
+
+const (
+	SlideLeft SlideDirection = iota
+	SlideRight
+	SlideUp
+	SlideDown
+)
+
+type slideTransition struct {
+	duration  time.Duration
+	direction SlideDirection
+}
+
+// Slide returns a transition pushing the old scene out of the screen with
+// the new scene
+func Slide(d time.Duration, direction SlideDirection) Transition {
+	return &slideTransition{d, direction}
+}
+
+func (t *slideTransition) Duration() time.Duration {
+	return t.duration
+}
+
+func (t *slideTransition) Render(progress float32, from, to func(float32)) {
+	// Offsets are in normalized device coordinates, with y up
+	var dx, dy float32
+	switch t.direction {
+	case SlideLeft:
+		dx = -1
+	case SlideRight:
+		dx = 1
+	case SlideUp:
+		dy = 1
+	case SlideDown:
+		dy = -1
+	}
+
+	withOffset(dx*progress, dy*progress, func() { from(1) })
+	withOffset(dx*(progress-1), dy*(progress-1), func() { to(1) })
+}
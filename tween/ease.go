@@ -0,0 +1,206 @@
+package tween
+
+import "math"
+
+// Ease maps the linear progress of a tween, from 0 to 1, to an eased
+// progress. These are Robert Penner's easing equations.
+type Ease func(t float32) float32
+
+// Linear doesn't ease
+func Linear(t float32) float32 {
+	return t
+}
+
+// QuadIn eases in with a quadratic curve
+func QuadIn(t float32) float32 {
+	return t * t
+}
+
+// QuadOut eases out with a quadratic curve
+func QuadOut(t float32) float32 {
+	return t * (2 - t)
+}
+
+// QuadInOut eases in and out with a quadratic curve
+func QuadInOut(t float32) float32 {
+	return inOut(QuadIn, t)
+}
+
+// CubicIn eases in with a cubic curve
+func CubicIn(t float32) float32 {
+	return t * t * t
+}
+
+// CubicOut eases out with a cubic curve
+func CubicOut(t float32) float32 {
+	return out(CubicIn, t)
+}
+
+// CubicInOut eases in and out with a cubic curve
+func CubicInOut(t float32) float32 {
+	return inOut(CubicIn, t)
+}
+
+// QuartIn eases in with a quartic curve
+func QuartIn(t float32) float32 {
+	return t * t * t * t
+}
+
+// QuartOut eases out with a quartic curve
+func QuartOut(t float32) float32 {
+	return out(QuartIn, t)
+}
+
+// QuartInOut eases in and out with a quartic curve
+func QuartInOut(t float32) float32 {
+	return inOut(QuartIn, t)
+}
+
+// QuintIn eases in with a quintic curve
+func QuintIn(t float32) float32 {
+	return t * t * t * t * t
+}
+
+// QuintOut eases out with a quintic curve
+func QuintOut(t float32) float32 {
+	return out(QuintIn, t)
+}
+
+// QuintInOut eases in and out with a quintic curve
+func QuintInOut(t float32) float32 {
+	return inOut(QuintIn, t)
+}
+
+// SineIn eases in with a sine curve
+func SineIn(t float32) float32 {
+	return 1 - float32(math.Cos(float64(t)*math.Pi/2))
+}
+
+// SineOut eases out with a sine curve
+func SineOut(t float32) float32 {
+	return float32(math.Sin(float64(t) * math.Pi / 2))
+}
+
+// SineInOut eases in and out with a sine curve
+func SineInOut(t float32) float32 {
+	return (1 - float32(math.Cos(float64(t)*math.Pi))) / 2
+}
+
+// ExpoIn eases in with an exponential curve
+func ExpoIn(t float32) float32 {
+	if t == 0 {
+		return 0
+	}
+	return float32(math.Pow(2, 10*float64(t-1)))
+}
+
+// ExpoOut eases out with an exponential curve
+func ExpoOut(t float32) float32 {
+	return out(ExpoIn, t)
+}
+
+// ExpoInOut eases in and out with an exponential curve
+func ExpoInOut(t float32) float32 {
+	return inOut(ExpoIn, t)
+}
+
+// CircIn eases in with a circular curve
+func CircIn(t float32) float32 {
+	return 1 - float32(math.Sqrt(float64(1-t*t)))
+}
+
+// CircOut eases out with a circular curve
+func CircOut(t float32) float32 {
+	return out(CircIn, t)
+}
+
+// CircInOut eases in and out with a circular curve
+func CircInOut(t float32) float32 {
+	return inOut(CircIn, t)
+}
+
+// backOvershoot is Penner's default overshoot of the back easings
+const backOvershoot = 1.70158
+
+// BackIn eases in with a curve first pulling back below 0
+func BackIn(t float32) float32 {
+	return backIn(backOvershoot, t)
+}
+
+// BackOut eases out with a curve overshooting 1 before settling
+func BackOut(t float32) float32 {
+	return out(BackIn, t)
+}
+
+// BackInOut eases in and out with a curve pulling back below 0 and
+// overshooting 1. Like Penner's, the overshoot is scaled by 1.525 since
+// each half covers half the distance.
+func BackInOut(t float32) float32 {
+	return inOut(func(t float32) float32 {
+		return backIn(backOvershoot*1.525, t)
+	}, t)
+}
+
+func backIn(s, t float32) float32 {
+	return t * t * ((s+1)*t - s)
+}
+
+// ElasticIn eases in with a decaying oscillation
+func ElasticIn(t float32) float32 {
+	if t == 0 || t == 1 {
+		return t
+	}
+	const period = 0.3
+	return -float32(math.Pow(2, 10*float64(t-1)) *
+		math.Sin((float64(t-1)-period/4)*2*math.Pi/period))
+}
+
+// ElasticOut eases out with a decaying oscillation
+func ElasticOut(t float32) float32 {
+	return out(ElasticIn, t)
+}
+
+// ElasticInOut eases in and out with a decaying oscillation
+func ElasticInOut(t float32) float32 {
+	return inOut(ElasticIn, t)
+}
+
+// BounceOut eases out with bounces
+func BounceOut(t float32) float32 {
+	switch {
+	case t < 1/2.75:
+		return 7.5625 * t * t
+	case t < 2/2.75:
+		t -= 1.5 / 2.75
+		return 7.5625*t*t + 0.75
+	case t < 2.5/2.75:
+		t -= 2.25 / 2.75
+		return 7.5625*t*t + 0.9375
+	default:
+		t -= 2.625 / 2.75
+		return 7.5625*t*t + 0.984375
+	}
+}
+
+// BounceIn eases in with bounces
+func BounceIn(t float32) float32 {
+	return out(BounceOut, t)
+}
+
+// BounceInOut eases in and out with bounces
+func BounceInOut(t float32) float32 {
+	return inOut(BounceIn, t)
+}
+
+// out returns the mirror of an ease in
+func out(in Ease, t float32) float32 {
+	return 1 - in(1-t)
+}
+
+// inOut plays an ease in for the first half and its mirror for the second
+func inOut(in Ease, t float32) float32 {
+	if t < 0.5 {
+		return in(t*2) / 2
+	}
+	return 1 - in((1-t)*2)/2
+}
@@ -0,0 +1,80 @@
+package tween
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+var eases = []struct {
+	name string
+	ease Ease
+}{
+	{"Linear", Linear},
+	{"QuadIn", QuadIn}, {"QuadOut", QuadOut}, {"QuadInOut", QuadInOut},
+	{"CubicIn", CubicIn}, {"CubicOut", CubicOut}, {"CubicInOut", CubicInOut},
+	{"QuartIn", QuartIn}, {"QuartOut", QuartOut}, {"QuartInOut", QuartInOut},
+	{"QuintIn", QuintIn}, {"QuintOut", QuintOut}, {"QuintInOut", QuintInOut},
+	{"SineIn", SineIn}, {"SineOut", SineOut}, {"SineInOut", SineInOut},
+	{"ExpoIn", ExpoIn}, {"ExpoOut", ExpoOut}, {"ExpoInOut", ExpoInOut},
+	{"CircIn", CircIn}, {"CircOut", CircOut}, {"CircInOut", CircInOut},
+	{"BackIn", BackIn}, {"BackOut", BackOut}, {"BackInOut", BackInOut},
+	{"ElasticIn", ElasticIn}, {"ElasticOut", ElasticOut}, {"ElasticInOut", ElasticInOut},
+	{"BounceIn", BounceIn}, {"BounceOut", BounceOut}, {"BounceInOut", BounceInOut},
+}
+
+func TestEaseEndpoints(t *testing.T) {
+	for _, e := range eases {
+		if got := e.ease(0); !near(got, 0) {
+			t.Errorf("%v(0) is %v, want 0", e.name, got)
+		}
+		if got := e.ease(1); !near(got, 1) {
+			t.Errorf("%v(1) is %v, want 1", e.name, got)
+		}
+	}
+}
+
+func TestEaseInOutSymmetry(t *testing.T) {
+	for _, e := range eases {
+		if e.name != "Linear" && e.name[len(e.name)-5:] != "InOut" {
+			continue
+		}
+		if got := e.ease(0.5); !near(got, 0.5) {
+			t.Errorf("%v(0.5) is %v, want 0.5", e.name, got)
+		}
+		for _, x := range []float32{0.1, 0.3, 0.45} {
+			if a, b := e.ease(x), e.ease(1-x); !near(a+b, 1) {
+				t.Errorf("%v(%v) + %v(%v) is %v, want 1", e.name, x, e.name, 1-x, a+b)
+			}
+		}
+	}
+}
+
+func TestEaseValues(t *testing.T) {
+	tests := []struct {
+		name string
+		ease Ease
+		in   float32
+		want float32
+	}{
+		{"QuadIn", QuadIn, 0.5, 0.25},
+		{"QuadOut", QuadOut, 0.5, 0.75},
+		{"CubicInOut", CubicInOut, 0.25, 0.0625},
+		{"SineOut", SineOut, 0.5, 0.7071068},
+		{"ExpoIn", ExpoIn, 0.5, 0.03125},
+		{"BackIn", BackIn, 0.5, -0.0876975},
+		{"BackOut", BackOut, 0.5, 1.0876975},
+		// Penner's easeInOutBack scales the overshoot by 1.525
+		{"BackInOut", BackInOut, 0.25, -0.0996818},
+		{"BackInOut", BackInOut, 0.75, 1.0996818},
+		{"BounceOut", BounceOut, 0.5, 0.765625},
+	}
+	for _, tt := range tests {
+		if got := tt.ease(tt.in); !near(got, tt.want) {
+			t.Errorf("%v(%v) is %v, want %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
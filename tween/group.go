@@ -0,0 +1,116 @@
+package tween
+
+import "time"
+
+type sequence struct {
+	actions []Action
+	current int
+}
+
+// Sequence creates an action running actions one after the other
+func Sequence(actions ...Action) Action {
+	return &sequence{actions: actions}
+}
+
+func (s *sequence) Start() {
+	s.current = 0
+	if len(s.actions) > 0 {
+		s.actions[0].Start()
+	}
+}
+
+func (s *sequence) Advance(dt time.Duration) (time.Duration, bool) {
+	for s.current < len(s.actions) {
+		left, done := s.actions[s.current].Advance(dt)
+		if !done {
+			return 0, false
+		}
+		s.current++
+		if s.current < len(s.actions) {
+			s.actions[s.current].Start()
+		}
+		dt = left
+	}
+	return dt, true
+}
+
+type parallel struct {
+	actions []Action
+	done    []bool
+}
+
+// Parallel creates an action running actions at the same time, it finishes
+// with the longest one
+func Parallel(actions ...Action) Action {
+	return &parallel{actions: actions, done: make([]bool, len(actions))}
+}
+
+func (p *parallel) Start() {
+	for i, a := range p.actions {
+		p.done[i] = false
+		a.Start()
+	}
+}
+
+func (p *parallel) Advance(dt time.Duration) (time.Duration, bool) {
+	finished := true
+	left := dt
+	for i, a := range p.actions {
+		if p.done[i] {
+			continue
+		}
+		l, done := a.Advance(dt)
+		if !done {
+			finished = false
+		} else if l < left {
+			left = l
+		}
+		p.done[i] = done
+	}
+	if !finished {
+		return 0, false
+	}
+	return left, true
+}
+
+type repeat struct {
+	action Action
+	times  int
+	count  int
+}
+
+// Repeat creates an action running an action a number of times, or
+// forever if times is 0 or less
+func Repeat(a Action, times int) Action {
+	return &repeat{action: a, times: times}
+}
+
+// Forever creates an action running an action until it is stopped
+func Forever(a Action) Action {
+	return Repeat(a, 0)
+}
+
+func (r *repeat) Start() {
+	r.count = 0
+	r.action.Start()
+}
+
+func (r *repeat) Advance(dt time.Duration) (time.Duration, bool) {
+	for {
+		left, done := r.action.Advance(dt)
+		if !done {
+			return 0, false
+		}
+		r.count++
+		if r.times > 0 && r.count >= r.times {
+			return left, true
+		}
+		r.action.Start()
+		if left == dt {
+			// The action takes no time, run it once per frame instead of
+			// looping forever
+			return 0, false
+		}
+		dt = left
+	}
+}
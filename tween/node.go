@@ -0,0 +1,108 @@
+package tween
+
+import (
+	"time"
+
+	"kiwanoengine.com/kiwano/geom"
+	"kiwanoengine.com/kiwano/render"
+)
+
+// Movable is a node which can be moved, like any node
+type Movable interface {
+	Position() geom.Vec2
+	SetPosition(x, y float32)
+}
+
+// Scalable is a node which can be scaled, like any node
+type Scalable interface {
+	Scale() geom.Vec2
+	SetScale(x, y float32)
+}
+
+// Rotatable is a node which can be rotated, like any node
+type Rotatable interface {
+	Rotation() float32
+	SetRotation(angle float32)
+}
+
+// Fadable is a node which can be faded, like any node
+type Fadable interface {
+	Opacity() float32
+	SetOpacity(opacity float32)
+}
+
+// Tintable is a node with a color, like a sprite
+type Tintable interface {
+	Color() render.Color
+	SetColor(color render.Color)
+}
+
+// MoveTo creates a tween moving a node to a position
+func MoveTo(n Movable, x, y float32, d time.Duration) *Tween {
+	var from geom.Vec2
+	return Custom(d, func() {
+		from = n.Position()
+	}, func(t float32) {
+		n.SetPosition(lerp(from.X, x, t), lerp(from.Y, y, t))
+	})
+}
+
+// MoveBy creates a tween moving a node by an offset
+func MoveBy(n Movable, dx, dy float32, d time.Duration) *Tween {
+	var from geom.Vec2
+	return Custom(d, func() {
+		from = n.Position()
+	}, func(t float32) {
+		n.SetPosition(from.X+dx*t, from.Y+dy*t)
+	})
+}
+
+// ScaleTo creates a tween scaling a node to a scale
+func ScaleTo(n Scalable, x, y float32, d time.Duration) *Tween {
+	var from geom.Vec2
+	return Custom(d, func() {
+		from = n.Scale()
+	}, func(t float32) {
+		n.SetScale(lerp(from.X, x, t), lerp(from.Y, y, t))
+	})
+}
+
+// RotateTo creates a tween rotating a node to an angle in degrees
+func RotateTo(n Rotatable, angle float32, d time.Duration) *Tween {
+	return To(n.Rotation, n.SetRotation, angle, d)
+}
+
+// RotateBy creates a tween rotating a node by an angle in degrees
+func RotateBy(n Rotatable, angle float32, d time.Duration) *Tween {
+	return By(n.Rotation, n.SetRotation, angle, d)
+}
+
+// FadeTo creates a tween changing the opacity of a node
+func FadeTo(n Fadable, opacity float32, d time.Duration) *Tween {
+	return To(n.Opacity, n.SetOpacity, opacity, d)
+}
+
+// FadeIn creates a tween making a node opaque
+func FadeIn(n Fadable, d time.Duration) *Tween {
+	return FadeTo(n, 1, d)
+}
+
+// FadeOut creates a tween making a node transparent
+func FadeOut(n Fadable, d time.Duration) *Tween {
+	return FadeTo(n, 0, d)
+}
+
+// TintTo creates a tween changing the color of a node
+func TintTo(n Tintable, color render.Color, d time.Duration) *Tween {
+	var from render.Color
+	return Custom(d, func() {
+		from = n.Color()
+	}, func(t float32) {
+		n.SetColor(render.Color{
+			R:     lerp(from.R, color.R, t),
+			G:     lerp(from.G, color.G, t),
+			B:     lerp(from.B, color.B, t),
+			Alpha: lerp(from.Alpha, color.Alpha, t),
+		})
+	})
+}
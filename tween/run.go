@@ -0,0 +1,85 @@
+package tween
+
+import (
+	"time"
+
+	"kiwanoengine.com/kiwano/node"
+)
+
+// Target is a node which can run actions, like any node
+type Target interface {
+	RunAction(node.Action)
+}
+
+// Player runs an action on a node, it is driven by the update delta of the
+// node's scene
+type Player struct {
+	action     Action
+	started    bool
+	stopped    bool
+	paused     bool
+	onComplete func()
+}
+
+// Run starts running an action on a node
+func Run(target Target, a Action) *Player {
+	p := &Player{action: a}
+	target.RunAction(p)
+	return p
+}
+
+// OnComplete sets a function called when the action finishes
+func (p *Player) OnComplete(fn func()) *Player {
+	p.onComplete = fn
+	return p
+}
+
+// Stop stops the action where it is, OnComplete is not called
+func (p *Player) Stop() {
+	p.stopped = true
+}
+
+// Pause suspends the action until Resume is called
+func (p *Player) Pause() {
+	p.paused = true
+}
+
+// Resume continues the action after Pause
+func (p *Player) Resume() {
+	p.paused = false
+}
+
+// Done reports whether the action finished or was stopped, directly or
+// with its node
+func (p *Player) Done() bool {
+	return p.stopped
+}
+
+// OnStop implements node.Stopper, OnComplete is not called
+func (p *Player) OnStop() {
+	p.stopped = true
+}
+
+// Step implements node.Action
+func (p *Player) Step(dt time.Duration) bool {
+	if p.stopped {
+		return true
+	}
+	if p.paused {
+		return false
+	}
+
+	if !p.started {
+		p.started = true
+		p.action.Start()
+	}
+	if _, done := p.action.Advance(dt); !done {
+		return false
+	}
+
+	p.stopped = true
+	if p.onComplete != nil {
+		p.onComplete()
+	}
+	return true
+}
@@ -0,0 +1,143 @@
+package tween
+
+import "time"
+
+// Action is an animation step which can be combined with others in
+// sequences, parallels and repeats, and run on a node with Run
+type Action interface {
+	// Start is called before the action runs, tweens read the current
+	// value of their property here
+	Start()
+	// Advance moves the action forward by dt. When the action finishes it
+	// returns true with the part of dt it did not use.
+	Advance(dt time.Duration) (left time.Duration, done bool)
+}
+
+// Tween interpolates values over a duration
+type Tween struct {
+	duration time.Duration
+	ease     Ease
+	yoyo     bool
+	elapsed  time.Duration
+
+	start func()
+	apply func(t float32)
+}
+
+// Custom creates a tween calling apply with the eased progress, from 0 to
+// 1, every frame. start, which may be nil, is called when the tween starts.
+func Custom(d time.Duration, start func(), apply func(t float32)) *Tween {
+	return &Tween{
+		duration: d,
+		ease:     Linear,
+		start:    start,
+		apply:    apply,
+	}
+}
+
+// FromTo creates a tween setting a value from one value to another
+func FromTo(from, to float32, d time.Duration, set func(float32)) *Tween {
+	return Custom(d, nil, func(t float32) {
+		set(lerp(from, to, t))
+	})
+}
+
+// To creates a tween setting a value from its value when the tween starts
+// to another value
+func To(get func() float32, set func(float32), to float32, d time.Duration) *Tween {
+	var from float32
+	return Custom(d, func() {
+		from = get()
+	}, func(t float32) {
+		set(lerp(from, to, t))
+	})
+}
+
+// By creates a tween adding delta to a value
+func By(get func() float32, set func(float32), delta float32, d time.Duration) *Tween {
+	var from float32
+	return Custom(d, func() {
+		from = get()
+	}, func(t float32) {
+		set(from + delta*t)
+	})
+}
+
+// WithEase changes the easing of the tween, Linear by default
+func (t *Tween) WithEase(ease Ease) *Tween {
+	t.ease = ease
+	return t
+}
+
+// Yoyo makes the tween play backward after playing forward, which doubles
+// its duration
+func (t *Tween) Yoyo() *Tween {
+	t.yoyo = true
+	return t
+}
+
+// Duration returns the duration of the tween, including the yoyo
+func (t *Tween) Duration() time.Duration {
+	if t.yoyo {
+		return t.duration * 2
+	}
+	return t.duration
+}
+
+func (t *Tween) Start() {
+	t.elapsed = 0
+	if t.start != nil {
+		t.start()
+	}
+}
+
+func (t *Tween) Advance(dt time.Duration) (time.Duration, bool) {
+	t.elapsed += dt
+	if total := t.Duration(); t.elapsed >= total {
+		if t.yoyo {
+			t.set(0)
+		} else {
+			t.set(1)
+		}
+		return t.elapsed - total, true
+	}
+
+	progress := float32(t.elapsed) / float32(t.duration)
+	if progress > 1 {
+		progress = 2 - progress
+	}
+	t.set(progress)
+	return 0, false
+}
+
+func (t *Tween) set(progress float32) {
+	if t.apply != nil {
+		t.apply(t.ease(progress))
+	}
+}
+
+// Delay creates an action waiting for a duration, to use in sequences
+func Delay(d time.Duration) *Tween {
+	return Custom(d, nil, nil)
+}
+
+type call struct {
+	fn func()
+}
+
+// Call creates an action calling a function, to use in sequences
+func Call(fn func()) Action {
+	return &call{fn}
+}
+
+func (c *call) Start() {
+}
+
+func (c *call) Advance(dt time.Duration) (time.Duration, bool) {
+	c.fn()
+	return dt, true
+}
+
+func lerp(from, to, t float32) float32 {
+	return from + (to-from)*t
+}
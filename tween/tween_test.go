@@ -0,0 +1,181 @@
+package tween
+
+import (
+	"testing"
+	"time"
+
+	"kiwanoengine.com/kiwano/node"
+)
+
+const ms = time.Millisecond
+
+func TestTweenYoyo(t *testing.T) {
+	var v float32
+	tw := FromTo(0, 10, 100*ms, func(x float32) { v = x }).Yoyo()
+	if tw.Duration() != 200*ms {
+		t.Fatalf("duration is %v, want 200ms", tw.Duration())
+	}
+	tw.Start()
+
+	steps := []struct {
+		dt   time.Duration
+		want float32
+		done bool
+	}{
+		{50 * ms, 5, false},
+		{50 * ms, 10, false},
+		{50 * ms, 5, false},
+		{60 * ms, 0, true},
+	}
+	for i, s := range steps {
+		left, done := tw.Advance(s.dt)
+		if !near(v, s.want) || done != s.done {
+			t.Fatalf("step %v: value %v done %v, want %v done %v", i, v, done, s.want, s.done)
+		}
+		if done && left != 10*ms {
+			t.Errorf("left %v, want 10ms", left)
+		}
+	}
+}
+
+func TestSequence(t *testing.T) {
+	var order []string
+	record := func(name string) Action {
+		return Call(func() { order = append(order, name) })
+	}
+	seq := Sequence(record("start"), Delay(100*ms), record("middle"), Delay(100*ms), record("end"))
+	seq.Start()
+
+	if _, done := seq.Advance(50 * ms); done || len(order) != 1 {
+		t.Fatalf("after 50ms: done %v, calls %v", done, order)
+	}
+	if _, done := seq.Advance(100 * ms); done || len(order) != 2 {
+		t.Fatalf("after 150ms: done %v, calls %v", done, order)
+	}
+	left, done := seq.Advance(80 * ms)
+	if !done || left != 30*ms {
+		t.Fatalf("after 230ms: done %v left %v, want done with 30ms left", done, left)
+	}
+	if want := []string{"start", "middle", "end"}; len(order) != 3 || order[2] != want[2] {
+		t.Errorf("calls %v, want %v", order, want)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	var a, b float32
+	par := Parallel(
+		FromTo(0, 1, 100*ms, func(x float32) { a = x }),
+		FromTo(0, 1, 300*ms, func(x float32) { b = x }),
+	)
+	par.Start()
+
+	if _, done := par.Advance(150 * ms); done || a != 1 || !near(b, 0.5) {
+		t.Fatalf("after 150ms: done %v, a %v b %v", done, a, b)
+	}
+	left, done := par.Advance(200 * ms)
+	if !done || left != 50*ms || b != 1 {
+		t.Fatalf("after 350ms: done %v left %v b %v, want done with 50ms left", done, left, b)
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	starts := 0
+	var v float32
+	rep := Repeat(Custom(100*ms, func() { starts++ }, func(x float32) { v = x }), 3)
+	rep.Start()
+
+	if _, done := rep.Advance(250 * ms); done || starts != 3 || !near(v, 0.5) {
+		t.Fatalf("after 250ms: done %v, %v starts, value %v", done, starts, v)
+	}
+	left, done := rep.Advance(60 * ms)
+	if !done || left != 10*ms || starts != 3 || v != 1 {
+		t.Fatalf("after 310ms: done %v left %v, %v starts, value %v", done, left, starts, v)
+	}
+}
+
+func TestRepeatYoyo(t *testing.T) {
+	var v float32
+	rep := Repeat(FromTo(0, 10, 100*ms, func(x float32) { v = x }).Yoyo(), 2)
+	rep.Start()
+
+	// Up and down, then up and down again on the second repetition
+	steps := []struct {
+		dt   time.Duration
+		want float32
+		done bool
+	}{
+		{50 * ms, 5, false},
+		{100 * ms, 5, false},
+		{100 * ms, 5, false},
+		{150 * ms, 0, true},
+	}
+	for i, s := range steps {
+		if _, done := rep.Advance(s.dt); done != s.done || !near(v, s.want) {
+			t.Fatalf("step %v: value %v done %v, want %v done %v", i, v, done, s.want, s.done)
+		}
+	}
+}
+
+func TestForeverZeroDuration(t *testing.T) {
+	calls := 0
+	forever := Forever(Call(func() { calls++ }))
+	forever.Start()
+	for i := 0; i < 3; i++ {
+		if _, done := forever.Advance(16 * ms); done {
+			t.Fatal("Forever finished")
+		}
+	}
+	if calls != 3 {
+		t.Errorf("%v calls, want one per step", calls)
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	tests := []struct {
+		name      string
+		run       func(parent, n *node.NodeProperties, p *Player)
+		done      bool
+		completed bool
+	}{
+		{"running", func(parent, n *node.NodeProperties, p *Player) {
+			node.Update(n, 50*ms)
+		}, false, false},
+		{"finished", func(parent, n *node.NodeProperties, p *Player) {
+			node.Update(n, 50*ms)
+			node.Update(n, 50*ms)
+		}, true, true},
+		{"paused", func(parent, n *node.NodeProperties, p *Player) {
+			p.Pause()
+			node.Update(n, 200*ms)
+		}, false, false},
+		{"stopped", func(parent, n *node.NodeProperties, p *Player) {
+			node.Update(n, 50*ms)
+			p.Stop()
+			node.Update(n, 50*ms)
+		}, true, false},
+		{"node removed", func(parent, n *node.NodeProperties, p *Player) {
+			node.Update(n, 50*ms)
+			parent.RemoveChild(n)
+			node.Update(n, 50*ms)
+		}, true, false},
+	}
+	for _, tt := range tests {
+		parent := node.New()
+		n := node.New()
+		parent.AddChild(n)
+
+		completed := 0
+		p := Run(n, FadeOut(n, 100*ms)).OnComplete(func() { completed++ })
+		tt.run(parent, n, p)
+
+		if p.Done() != tt.done {
+			t.Errorf("%v: done is %v, want %v", tt.name, p.Done(), tt.done)
+		}
+		if (completed == 1) != tt.completed || completed > 1 {
+			t.Errorf("%v: OnComplete called %v times, want completed %v", tt.name, completed, tt.completed)
+		}
+		if tt.done && n.NumActions() != 0 {
+			t.Errorf("%v: %v actions left on the node", tt.name, n.NumActions())
+		}
+	}
+}
@@ -1,9 +1,26 @@
 package kiwano
 
 import (
+	"image"
+
 	"github.com/go-gl/glfw/v3.2/glfw"
 
-	"kiwanoengine.com/kiwano/external/gl"
+	"kiwanoengine.com/kiwano/geom"
+	"kiwanoengine.com/kiwano/render"
+)
+
+// ResolutionPolicy decides how the design resolution is fitted into the
+// window
+type ResolutionPolicy = render.ResolutionPolicy
+
+// Resolution policies, see render.ResolutionPolicy
+const (
+	ExactFit     = render.ExactFit
+	ShowAll      = render.ShowAll
+	NoBorder     = render.NoBorder
+	FixedWidth   = render.FixedWidth
+	FixedHeight  = render.FixedHeight
+	PixelPerfect = render.PixelPerfect
 )
 
 type Option struct {
@@ -14,11 +31,30 @@ type Option struct {
 	Fullscreen    bool
 	Resizable     bool
 	Vsync         bool
+	// DesignWidth and DesignHeight are the resolution the game is made
+	// for, the view is scaled to the window following ResolutionPolicy.
+	// Without them one unit is one screen coordinate, on HiDPI displays too.
+	DesignWidth, DesignHeight int
+	ResolutionPolicy          ResolutionPolicy
+	// TargetFPS caps the frame rate when Vsync is off, 0 for no limit
+	TargetFPS int
+	// TickRate is the number of fixed updates per second, 60 by default
+	TickRate int
+	// MaxFixedSteps limits the fixed updates run in one frame, 5 by default
+	MaxFixedSteps int
+	// NoWindow runs without a window or GPU, frames are rasterized by a
+	// render.SoftwareBackend
+	NoWindow bool
 }
 
 type Window struct {
 	Option
-	glfw.Window
+	*glfw.Window
+
+	shouldClose bool
+
+	// windowed is the geometry restored when leaving fullscreen
+	windowed struct{ x, y, width, height int }
 }
 
 func NewWindow(option *Option) (*Window, error) {
@@ -27,6 +63,13 @@ func NewWindow(option *Option) (*Window, error) {
 		Option: *option,
 	}
 
+	if option.NoWindow {
+		render.SetBackend(render.NewSoftwareBackend(option.Width, option.Height))
+		render.SetDesignResolution(option.DesignWidth, option.DesignHeight, option.ResolutionPolicy)
+		render.Resize(option.Width, option.Height)
+		return window, nil
+	}
+
 	// Init GLFW
 	if err := glfw.Init(); err != nil {
 		return nil, err
@@ -70,12 +113,27 @@ func NewWindow(option *Option) (*Window, error) {
 		return nil, err
 	}
 
+	window.windowed.width, window.windowed.height = window.Width, window.Height
+	if monitorMode != nil {
+		window.windowed.x = (monitorMode.Width - window.Width) / 2
+		window.windowed.y = (monitorMode.Height - window.Height) / 2
+	}
 	if !option.Fullscreen && monitorMode != nil {
-		w.SetPos((monitorMode.Width-option.Width)/2, (monitorMode.Height-option.Height)/2)
+		w.SetPos(window.windowed.x, window.windowed.y)
 	}
 
 	w.MakeContextCurrent()
 	w.SetFramebufferSizeCallback(window.onFramebufferSizeCallback)
+	w.SetFocusCallback(window.onFocusCallback)
+	w.SetIconifyCallback(window.onIconifyCallback)
+	w.SetPosCallback(window.onPosCallback)
+	w.SetCloseCallback(window.onCloseCallback)
+	w.SetKeyCallback(window.onKeyCallback)
+	w.SetCharCallback(window.onCharCallback)
+	w.SetMouseButtonCallback(window.onMouseButtonCallback)
+	w.SetCursorPosCallback(window.onCursorPosCallback)
+	w.SetCursorEnterCallback(window.onCursorEnterCallback)
+	w.SetScrollCallback(window.onScrollCallback)
 
 	if option.Vsync {
 		glfw.SwapInterval(1)
@@ -84,17 +142,359 @@ func NewWindow(option *Option) (*Window, error) {
 	}
 
 	// Init OpenGL
-	if err := gl.Init(); err != nil {
+	backend, err := render.NewGLBackend()
+	if err != nil {
 		return nil, err
 	}
+	render.SetBackend(backend)
 
-	gl.ClearColor(option.ClearColor.ToVec4())
-
-	window.Window = *w
+	window.Window = w
+	render.SetDesignResolution(option.DesignWidth, option.DesignHeight, option.ResolutionPolicy)
+	render.SetPixelRatio(window.pixelRatio())
+	render.Resize(w.GetFramebufferSize())
 	return window, nil
 }
 
+// Headless reports whether the window was created without GLFW
+func (w *Window) Headless() bool {
+	return w.Window == nil
+}
+
+// Show ...
+func (w *Window) Show() {
+	if w.Window != nil {
+		w.Window.Show()
+	}
+}
+
+// ShouldClose ...
+func (w *Window) ShouldClose() bool {
+	if w.Window == nil {
+		return w.shouldClose
+	}
+	return w.Window.ShouldClose()
+}
+
+// SetShouldClose ...
+func (w *Window) SetShouldClose(value bool) {
+	if w.Window == nil {
+		w.shouldClose = value
+		return
+	}
+	w.Window.SetShouldClose(value)
+}
+
+// SwapBuffers ...
+func (w *Window) SwapBuffers() {
+	if w.Window != nil {
+		w.Window.SwapBuffers()
+	}
+}
+
+// Destroy ...
+func (w *Window) Destroy() {
+	if w.Window != nil {
+		w.Window.Destroy()
+	}
+}
+
+// SetTitle changes the title of the window
+func (w *Window) SetTitle(title string) {
+	w.Title = title
+	if w.Window != nil {
+		w.Window.SetTitle(title)
+	}
+}
+
+// SetIcon changes the icon of the window, the system picks the image with
+// the closest size among the candidates. No image restores the default.
+func (w *Window) SetIcon(images ...image.Image) {
+	if w.Window != nil {
+		w.Window.SetIcon(images)
+	}
+}
+
+// SetFullscreen switches the window to exclusive fullscreen on a monitor
+// with a video mode. A nil monitor is the primary monitor and a zero mode
+// the current mode of the monitor.
+func (w *Window) SetFullscreen(monitor *Monitor, mode VideoMode) {
+	if w.Window == nil {
+		return
+	}
+	if monitor == nil {
+		if monitor = PrimaryMonitor(); monitor == nil {
+			return
+		}
+	}
+	if mode == (VideoMode{}) {
+		mode = monitor.VideoMode()
+	}
+
+	w.saveWindowed()
+	w.Fullscreen = true
+	w.Window.SetMonitor(monitor.monitor, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+	w.applyVsync()
+}
+
+// SetBorderlessFullscreen covers a monitor with the window without changing
+// its video mode, which switches faster than SetFullscreen. A nil monitor
+// is the primary monitor.
+func (w *Window) SetBorderlessFullscreen(monitor *Monitor) {
+	if monitor == nil {
+		monitor = PrimaryMonitor()
+	}
+	if monitor != nil {
+		// GLFW keeps the video mode when the current one is requested
+		w.SetFullscreen(monitor, monitor.VideoMode())
+	}
+}
+
+// SetWindowed leaves fullscreen and restores the previous window geometry
+func (w *Window) SetWindowed() {
+	if w.Window == nil || w.Window.GetMonitor() == nil {
+		return
+	}
+	w.Fullscreen = false
+	r := w.windowed
+	w.Window.SetMonitor(nil, r.x, r.y, r.width, r.height, 0)
+	w.applyVsync()
+}
+
+// ToggleFullscreen switches between windowed and borderless fullscreen on
+// the current monitor
+func (w *Window) ToggleFullscreen() {
+	if w.IsFullscreen() {
+		w.SetWindowed()
+	} else {
+		w.SetBorderlessFullscreen(w.CurrentMonitor())
+	}
+}
+
+// IsFullscreen reports whether the window is fullscreen
+func (w *Window) IsFullscreen() bool {
+	return w.Window != nil && w.Window.GetMonitor() != nil
+}
+
+// CurrentMonitor returns the monitor of a fullscreen window, or the monitor
+// containing the center of a windowed one
+func (w *Window) CurrentMonitor() *Monitor {
+	if w.Window == nil {
+		return nil
+	}
+	if m := w.Window.GetMonitor(); m != nil {
+		return &Monitor{m}
+	}
+
+	x, y := w.GetPos()
+	width, height := w.GetSize()
+	cx, cy := x+width/2, y+height/2
+	for _, m := range Monitors() {
+		mx, my := m.Position()
+		mode := m.VideoMode()
+		if cx >= mx && cx < mx+mode.Width && cy >= my && cy < my+mode.Height {
+			return m
+		}
+	}
+	return PrimaryMonitor()
+}
+
+// SetResolution resizes a windowed window, or changes the video mode of a
+// fullscreen one
+func (w *Window) SetResolution(width, height int) {
+	if w.Window == nil {
+		return
+	}
+	if m := w.Window.GetMonitor(); m != nil {
+		w.Window.SetMonitor(m, 0, 0, width, height, glfw.DontCare)
+		w.applyVsync()
+		return
+	}
+	w.Window.SetSize(width, height)
+}
+
+// SetSizeLimits limits the size of a resizable window, 0 removes a limit
+func (w *Window) SetSizeLimits(minWidth, minHeight, maxWidth, maxHeight int) {
+	if w.Window == nil {
+		return
+	}
+	w.Window.SetSizeLimits(dontCare(minWidth), dontCare(minHeight), dontCare(maxWidth), dontCare(maxHeight))
+}
+
+// SetAspectRatio keeps the aspect ratio of a resizable window, 0 removes
+// the constraint
+func (w *Window) SetAspectRatio(numerator, denominator int) {
+	if w.Window == nil {
+		return
+	}
+	if numerator <= 0 || denominator <= 0 {
+		numerator, denominator = glfw.DontCare, glfw.DontCare
+	}
+	w.Window.SetAspectRatio(numerator, denominator)
+}
+
+// Minimize iconifies the window
+func (w *Window) Minimize() error {
+	if w.Window == nil {
+		return nil
+	}
+	return w.Window.Iconify()
+}
+
+// Maximize enlarges the window to fill the work area of its monitor
+func (w *Window) Maximize() error {
+	if w.Window == nil {
+		return nil
+	}
+	return w.Window.Maximize()
+}
+
+// Restore restores a minimized or maximized window
+func (w *Window) Restore() error {
+	if w.Window == nil {
+		return nil
+	}
+	return w.Window.Restore()
+}
+
+// IsMinimized reports whether the window is iconified
+func (w *Window) IsMinimized() bool {
+	return w.Window != nil && w.GetAttrib(glfw.Iconified) == glfw.True
+}
+
+// IsMaximized reports whether the window is maximized
+func (w *Window) IsMaximized() bool {
+	return w.Window != nil && w.GetAttrib(glfw.Maximized) == glfw.True
+}
+
+// IsFocused reports whether the window has the input focus
+func (w *Window) IsFocused() bool {
+	return w.Window != nil && w.GetAttrib(glfw.Focused) == glfw.True
+}
+
+// saveWindowed records the geometry of a windowed window before it goes
+// fullscreen
+func (w *Window) saveWindowed() {
+	if w.Window.GetMonitor() != nil {
+		return
+	}
+	w.windowed.x, w.windowed.y = w.GetPos()
+	w.windowed.width, w.windowed.height = w.GetSize()
+}
+
+// applyVsync sets the swap interval again, some platforms reset it when the
+// monitor of the window changes
+func (w *Window) applyVsync() {
+	if w.Vsync {
+		glfw.SwapInterval(1)
+	} else {
+		glfw.SwapInterval(0)
+	}
+}
+
+func dontCare(size int) int {
+	if size <= 0 {
+		return glfw.DontCare
+	}
+	return size
+}
+
+// ScreenToWorld converts a point in window coordinates, like the cursor
+// position, to world coordinates through the camera of the current scene
+func (w *Window) ScreenToWorld(x, y float64) geom.Vec2 {
+	p := w.ScreenToView(x, y)
+	if camera := sceneCamera(CurrentScene); camera != nil {
+		p = camera.ViewToWorld(p)
+	}
+	return p
+}
+
+// WorldToScreen converts a point in world coordinates to window coordinates
+// through the camera of the current scene
+func (w *Window) WorldToScreen(p geom.Vec2) (float64, float64) {
+	if camera := sceneCamera(CurrentScene); camera != nil {
+		p = camera.WorldToView(p)
+	}
+	return w.ViewToScreen(p)
+}
+
+// ScreenToView converts a point in window coordinates to view coordinates,
+// which ignore cameras, for user interfaces drawn over the world
+func (w *Window) ScreenToView(x, y float64) geom.Vec2 {
+	sx, sy := w.contentScale()
+	return render.Unproject(float32(x)*sx, float32(y)*sy)
+}
+
+// ViewToScreen converts a point in view coordinates to window coordinates
+func (w *Window) ViewToScreen(p geom.Vec2) (float64, float64) {
+	sx, sy := w.contentScale()
+	x, y := render.Project(p)
+	return float64(x / sx), float64(y / sy)
+}
+
+// SetDesignResolution changes the resolution the game is made for and how
+// it is fitted into the window, a size of 0 removes it
+func (w *Window) SetDesignResolution(width, height int, policy ResolutionPolicy) {
+	w.DesignWidth, w.DesignHeight = width, height
+	w.ResolutionPolicy = policy
+	render.SetDesignResolution(width, height, policy)
+}
+
+// pixelRatio returns the number of framebuffer pixels per window coordinate
+func (w *Window) pixelRatio() float32 {
+	sx, _ := w.contentScale()
+	return sx
+}
+
+// contentScale returns the ratio between framebuffer pixels and window
+// coordinates, which is greater than 1 on HiDPI displays
+func (w *Window) contentScale() (float32, float32) {
+	if w.Window == nil {
+		return 1, 1
+	}
+	width, height := w.GetSize()
+	fbWidth, fbHeight := w.GetFramebufferSize()
+	if width == 0 || height == 0 {
+		return 1, 1
+	}
+	return float32(fbWidth) / float32(width), float32(fbHeight) / float32(height)
+}
+
 func (w *Window) onFramebufferSizeCallback(win *glfw.Window, width int, height int) {
-	w.Width, w.Height = width, height
-	gl.Viewport(0, 0, int32(width), int32(height))
+	// Width and Height are in window coordinates, which differ from pixels
+	// on HiDPI displays
+	w.Width, w.Height = win.GetSize()
+	if width > 0 && height > 0 {
+		render.SetPixelRatio(w.pixelRatio())
+	}
+	render.Resize(width, height)
+
+	for _, scene := range Scenes() {
+		if s, ok := scene.(Resizable); ok {
+			s.OnResize(width, height)
+		}
+	}
+}
+
+func (w *Window) onFocusCallback(win *glfw.Window, focused bool) {
+	if s, ok := CurrentScene.(FocusAware); ok {
+		s.OnFocusChange(focused)
+	}
+}
+
+func (w *Window) onIconifyCallback(win *glfw.Window, iconified bool) {
+	if s, ok := CurrentScene.(IconifyAware); ok {
+		s.OnIconify(iconified)
+	}
+}
+
+func (w *Window) onPosCallback(win *glfw.Window, x, y int) {
+	if s, ok := CurrentScene.(MoveAware); ok {
+		s.OnMove(x, y)
+	}
+}
+
+func (w *Window) onCloseCallback(win *glfw.Window) {
+	if s, ok := CurrentScene.(CloseHandler); ok && !s.OnCloseRequested() {
+		win.SetShouldClose(false)
+	}
 }